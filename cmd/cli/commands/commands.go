@@ -3,11 +3,33 @@ package commands
 import (
 	"encoding/hex"
 	"fmt"
+	"strings"
 
+	"github.com/evercoinx/bitcoin/internal/address"
+	"github.com/evercoinx/bitcoin/internal/chaincfg"
+	"github.com/evercoinx/bitcoin/internal/crypto"
 	"github.com/evercoinx/bitcoin/internal/encoding"
 	"github.com/urfave/cli/v2"
 )
 
+func GetFlags() []cli.Flag {
+	return []cli.Flag{
+		networkFlag(),
+	}
+}
+
+// networkFlag returns the flag of the network. It is defined both globally
+// and on every command which depends on the network, so it can be given
+// before or after the command name.
+func networkFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "network",
+		Aliases: []string{"n"},
+		Value:   chaincfg.MainNetParams.Name,
+		Usage:   "network: " + strings.Join(chaincfg.NetworkNames(), ", ") + " (testnet is an alias of testnet3)",
+	}
+}
+
 func GetCommands() []*cli.Command {
 	return []*cli.Command{
 		{
@@ -17,31 +39,121 @@ func GetCommands() []*cli.Command {
 				{
 					Name:    "encode",
 					Aliases: []string{"e"},
-					Usage:   "encode hash of public key or script to bitcoin address",
+					Usage:   "encode hash of public key or script, or taproot output key to bitcoin address",
 					Action:  encodeHashToAddress,
 					Flags: []cli.Flag{
+						networkFlag(),
 						&cli.StringFlag{
 							Name:    "address-type",
 							Aliases: []string{"t"},
 							Value:   "p2pkh",
-							Usage:   "address type: p2pkh or p2sh",
+							Usage:   "address type: p2pkh, p2sh, p2wpkh, p2wsh or p2tr",
 						},
 					},
 				},
 				{
 					Name:    "decode",
 					Aliases: []string{"d"},
-					Usage:   "decode bitcoin address to hash of public key or script",
-					Action:  decodeAddressToHash,
+					Usage:   "decode bitcoin address to its network, type and hash or witness program",
+					Action:  decodeAddress,
+					Flags: []cli.Flag{
+						networkFlag(),
+					},
+				},
+				{
+					Name:    "from-pubkey",
+					Aliases: []string{"p"},
+					Usage:   "derive bitcoin addresses of all applicable types from public key in sec format",
+					Action:  deriveAddressesFromPubKey,
+					Flags: []cli.Flag{
+						networkFlag(),
+					},
+				},
+				{
+					Name:    "from-script",
+					Aliases: []string{"s"},
+					Usage:   "derive bitcoin addresses of all applicable types from script",
+					Action:  deriveAddressesFromScript,
+					Flags: []cli.Flag{
+						networkFlag(),
+					},
+				},
+			},
+		},
+		{
+			Name:    "key",
+			Aliases: []string{"k"},
+			Subcommands: []*cli.Command{
+				{
+					Name:    "wif",
+					Aliases: []string{"w"},
+					Subcommands: []*cli.Command{
+						{
+							Name:    "encode",
+							Aliases: []string{"e"},
+							Usage:   "encode private key to wallet import format",
+							Action:  encodeKeyToWIF,
+							Flags: []cli.Flag{
+								networkFlag(),
+								&cli.BoolFlag{
+									Name:    "uncompressed",
+									Aliases: []string{"u"},
+									Usage:   "use uncompressed public key",
+								},
+							},
+						},
+						{
+							Name:    "decode",
+							Aliases: []string{"d"},
+							Usage:   "decode private key from wallet import format",
+							Action:  decodeWIFToKey,
+							Flags: []cli.Flag{
+								networkFlag(),
+							},
+						},
+					},
 				},
 			},
 		},
 	}
 }
 
+// networkName returns the network given to the innermost command which has
+// the flag set, and whether the flag is set at all.
+func networkName(ctx *cli.Context) (string, bool) {
+	for _, c := range ctx.Lineage() {
+		for _, name := range c.LocalFlagNames() {
+			if name == "network" || name == "n" {
+				return c.String("network"), true
+			}
+		}
+	}
+	return chaincfg.MainNetParams.Name, false
+}
+
+func networkParams(ctx *cli.Context) (*chaincfg.Params, error) {
+	name, _ := networkName(ctx)
+	params, err := chaincfg.ParamsByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network is specified.\ncause: %w", err)
+	}
+	return params, nil
+}
+
 func encodeHashToAddress(ctx *cli.Context) error {
+	params, err := networkParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	addrType := ctx.String("address-type")
+	hashSize := 20
+	if addrType == "p2wsh" || addrType == "p2tr" {
+		hashSize = 32
+	}
+
 	hash := ctx.Args().First()
-	if len(hash) != 40 {
+	if len(hash) != 2*hashSize {
 		return fmt.Errorf("invalid hash is specified: %s", hash)
 	}
 
@@ -50,32 +162,219 @@ func encodeHashToAddress(ctx *cli.Context) error {
 		return fmt.Errorf("unable to decode hash.\ncause: %w", err)
 	}
 
-	var ver encoding.AddressVersion
-	switch ctx.String("address-type") {
+	var addr string
+	switch addrType {
 	case "p2pkh":
-		ver = encoding.AddressVersionPublicKeyHash
+		addr = encoding.Base58CheckEncode(payload, encoding.AddressVersion(params.PubKeyHashAddrID))
 	case "p2sh":
-		ver = encoding.AddressVersionScriptHash
+		addr = encoding.Base58CheckEncode(payload, encoding.AddressVersion(params.ScriptHashAddrID))
+	case "p2wpkh", "p2wsh":
+		addr, err = encoding.SegwitAddressEncode(params.Bech32HRP, 0, payload)
+	case "p2tr":
+		addr, err = encoding.SegwitAddressEncode(params.Bech32HRP, 1, payload)
 	default:
-		return fmt.Errorf("invalid address type is specified: %s", ver)
+		return fmt.Errorf("invalid address type is specified: %s", addrType)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to encode address.\ncause: %w", err)
 	}
 
-	addr := encoding.Base58CheckEncode(payload, ver)
 	fmt.Printf("address: %s\n", addr)
 	return nil
 }
 
-func decodeAddressToHash(ctx *cli.Context) error {
+func decodeAddress(ctx *cli.Context) error {
 	addr := ctx.Args().First()
-	if len(addr) < 14 || len(addr) > 74 {
+	if len(addr) < 14 || len(addr) > 90 {
 		return fmt.Errorf("invalid address is specified: %s", addr)
 	}
 
-	hash, err := encoding.Base58CheckDecode(addr)
+	// the network is detected from the address unless it is set explicitly
+	var decoded *address.Address
+	var err error
+	if _, ok := networkName(ctx); ok {
+		params, perr := networkParams(ctx)
+		if perr != nil {
+			return perr
+		}
+		decoded, err = address.Decode(addr, params)
+	} else {
+		decoded, err = address.Parse(addr)
+	}
 	if err != nil {
+		if locs := encoding.Bech32LocateErrors(addr); len(locs) > 0 {
+			return fmt.Errorf("unable to decode address.\ncause: %w\nsuspect characters:\n%s\n%s",
+				err, addr, highlightPositions(len(addr), locs))
+		}
 		return fmt.Errorf("unable to decode address.\ncause: %w", err)
 	}
 
-	fmt.Printf("hash: %x\n", hash)
+	fmt.Printf("network: %s\n", decoded.Network.Name)
+	fmt.Printf("type: %s\n", decoded.Type)
+	if decoded.Type.IsSegwit() {
+		fmt.Printf("witness version: %d\n", decoded.WitnessVersion)
+		fmt.Printf("witness program: %x\n", decoded.Program)
+	} else {
+		fmt.Printf("hash: %x\n", decoded.Program)
+	}
+	return nil
+}
+
+func deriveAddressesFromPubKey(ctx *cli.Context) error {
+	params, err := networkParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	key := ctx.Args().First()
+	if len(key) != 66 && len(key) != 130 {
+		return fmt.Errorf("invalid public key is specified: %s", key)
+	}
+
+	pubKey, err := hex.DecodeString(key)
+	if err != nil {
+		return fmt.Errorf("unable to decode public key.\ncause: %w", err)
+	}
+
+	derivers := []addressDeriver{
+		{"p2pkh", address.NewP2PKH},
+	}
+	// p2wpkh and p2sh-p2wpkh addresses are defined for compressed public
+	// keys only, while p2tr takes the x coordinate of any key
+	if len(pubKey) == crypto.PublicKeyCompressedSize {
+		derivers = append(derivers,
+			addressDeriver{"p2sh-p2wpkh", address.NewP2SHP2WPKH},
+			addressDeriver{"p2wpkh", address.NewP2WPKH},
+		)
+	}
+	derivers = append(derivers, addressDeriver{"p2tr", address.NewP2TR})
+	return printDerivedAddresses(pubKey, params, derivers)
+}
+
+func deriveAddressesFromScript(ctx *cli.Context) error {
+	params, err := networkParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	script, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode script.\ncause: %w", err)
+	}
+	if len(script) == 0 {
+		return fmt.Errorf("invalid script is specified: %s", ctx.Args().First())
+	}
+
+	return printDerivedAddresses(script, params, []addressDeriver{
+		{"p2sh", address.NewP2SH},
+		{"p2wsh", address.NewP2WSH},
+	})
+}
+
+type addressDeriver struct {
+	name   string
+	derive func([]byte, *chaincfg.Params) (*address.Address, error)
+}
+
+func printDerivedAddresses(data []byte, params *chaincfg.Params, derivers []addressDeriver) error {
+	for _, d := range derivers {
+		addr, err := d.derive(data, params)
+		if err != nil {
+			return fmt.Errorf("unable to derive %s address.\ncause: %w", d.name, err)
+		}
+		fmt.Printf("%s: %s\n", d.name, addr)
+	}
+	return nil
+}
+
+// highlightPositions returns a line of the given length with carets under
+// the positions.
+func highlightPositions(length int, positions []int) string {
+	line := []byte(strings.Repeat(" ", length))
+	for _, p := range positions {
+		line[p] = '^'
+	}
+	return strings.TrimRight(string(line), " ")
+}
+
+func encodeKeyToWIF(ctx *cli.Context) error {
+	params, err := networkParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	key := ctx.Args().First()
+	if len(key) != 64 {
+		return fmt.Errorf("invalid private key is specified: %s", key)
+	}
+
+	keyBytes, err := hex.DecodeString(key)
+	if err != nil {
+		return fmt.Errorf("unable to decode private key.\ncause: %w", err)
+	}
+	if _, err := crypto.NewPrivateKey(keyBytes); err != nil {
+		return fmt.Errorf("invalid private key is specified.\ncause: %w", err)
+	}
+
+	ver := encoding.WIFVersion(params.PrivateKeyID)
+	wif, err := encoding.WIFEncode(keyBytes, ver, !ctx.Bool("uncompressed"))
+	if err != nil {
+		return fmt.Errorf("unable to encode private key.\ncause: %w", err)
+	}
+
+	fmt.Printf("wif: %s\n", wif)
+	return nil
+}
+
+func decodeWIFToKey(ctx *cli.Context) error {
+	wif := ctx.Args().First()
+	if len(wif) != 51 && len(wif) != 52 {
+		return fmt.Errorf("invalid wif is specified: %s", wif)
+	}
+
+	decoded, err := encoding.WIFDecode(wif)
+	if err != nil {
+		return fmt.Errorf("unable to decode wif.\ncause: %w", err)
+	}
+
+	// the network is detected from the version unless it is set explicitly
+	var params *chaincfg.Params
+	if _, ok := networkName(ctx); ok {
+		if params, err = networkParams(ctx); err != nil {
+			return err
+		}
+		if byte(decoded.Version) != params.PrivateKeyID {
+			return fmt.Errorf("wif is not for %s: version 0x%02x", params.Name, byte(decoded.Version))
+		}
+	} else if params = wifNetwork(byte(decoded.Version)); params == nil {
+		return fmt.Errorf("wif is for unknown network: version 0x%02x", byte(decoded.Version))
+	}
+
+	key, err := crypto.NewPrivateKey(decoded.Key)
+	if err != nil {
+		return fmt.Errorf("invalid private key is decoded.\ncause: %w", err)
+	}
+
+	pubKey := key.SerializeUncompressed()
+	if decoded.Compressed {
+		pubKey = key.SerializeCompressed()
+	}
+
+	fmt.Printf("private key: %x\n", key.Bytes())
+	fmt.Printf("public key: %x\n", pubKey)
+	fmt.Printf("network: %s\n", params.Name)
+	fmt.Printf("compressed: %t\n", decoded.Compressed)
+	return nil
+}
+
+// wifNetwork returns the first network of chaincfg.Networks with the given
+// private key version, or nil if there is none. Test networks share the
+// version, so their keys are attributed to testnet3.
+func wifNetwork(ver byte) *chaincfg.Params {
+	for _, params := range chaincfg.Networks {
+		if params.PrivateKeyID == ver {
+			return params
+		}
+	}
 	return nil
 }
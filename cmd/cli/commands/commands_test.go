@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+// runApp runs the command line application with the given arguments and
+// returns what it printed to the standard output.
+func runApp(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unable to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	app := &cli.App{
+		Name:                   "bitcoin",
+		Flags:                  GetFlags(),
+		Commands:               GetCommands(),
+		UseShortOptionHandling: true,
+	}
+	runErr := app.Run(append([]string{"bitcoin"}, args...))
+
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unable to read output: %v", err)
+	}
+	return string(out), runErr
+}
+
+func TestDecodeWIFToKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		network string
+	}{
+		{
+			"mainnet wif without network",
+			[]string{"key", "wif", "decode", "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ"},
+			"network: mainnet",
+		},
+		{
+			"testnet wif without network",
+			[]string{"key", "wif", "decode", "91gGn1HgSap6CbU12F6z3pJri26xzp7Ay1VW6NHCoEayNXwRpu2"},
+			"network: testnet3",
+		},
+		{
+			"testnet wif with network",
+			[]string{"-n", "testnet4", "key", "wif", "decode", "91gGn1HgSap6CbU12F6z3pJri26xzp7Ay1VW6NHCoEayNXwRpu2"},
+			"network: testnet4",
+		},
+	}
+
+	for _, tt := range tests {
+		out, err := runApp(t, tt.args...)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !strings.Contains(out, tt.network+"\n") {
+			t.Errorf("%s: output %q does not contain %q", tt.name, out, tt.network)
+		}
+	}
+}
+
+func TestDecodeWIFToKeyNetworkMismatch(t *testing.T) {
+	_, err := runApp(t, "-n", "mainnet", "key", "wif", "decode", "91gGn1HgSap6CbU12F6z3pJri26xzp7Ay1VW6NHCoEayNXwRpu2")
+	if err == nil || !strings.Contains(err.Error(), "wif is not for mainnet") {
+		t.Errorf("expected network mismatch error, got %v", err)
+	}
+}
+
+func TestNetworkFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			"wif encode with subcommand network alias",
+			[]string{"key", "wif", "encode", "-n", "testnet", "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d"},
+			"wif: cMzLdeGd5vEqxB8B6VFQoRopQ3sLAAvEzDAoQgvX54xwofSWj1fx",
+		},
+		{
+			"wif encode with global network",
+			[]string{"-n", "testnet3", "key", "wif", "encode", "-u", "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d"},
+			"wif: 91gGn1HgSap6CbU12F6z3pJri26xzp7Ay1VW6NHCoEayNXwRpu2",
+		},
+		{
+			"address encode with subcommand network",
+			[]string{"address", "encode", "-n", "testnet3", "751e76e8199196d454941c45d1b3a323f1433bd6"},
+			"address: mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r",
+		},
+		{
+			"address encode with global network",
+			[]string{"--network", "testnet3", "address", "encode", "751e76e8199196d454941c45d1b3a323f1433bd6"},
+			"address: mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r",
+		},
+		{
+			"subcommand network overrides global one",
+			[]string{"-n", "mainnet", "address", "encode", "--network", "testnet3", "751e76e8199196d454941c45d1b3a323f1433bd6"},
+			"address: mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r",
+		},
+		{
+			"address decode with subcommand network",
+			[]string{"address", "decode", "-n", "testnet4", "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"},
+			"network: testnet4",
+		},
+	}
+
+	for _, tt := range tests {
+		out, err := runApp(t, tt.args...)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !strings.Contains(out, tt.want+"\n") {
+			t.Errorf("%s: output %q does not contain %q", tt.name, out, tt.want)
+		}
+	}
+}
+
+func TestDeriveAddressesFromPubKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		pubKey string
+		want   string
+	}{
+		{
+			"compressed key",
+			"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			"p2pkh: 1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH\n" +
+				"p2sh-p2wpkh: 3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN\n" +
+				"p2wpkh: bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4\n" +
+				"p2tr: bc1pmfr3p9j00pfxjh0zmgp99y8zftmd3s5pmedqhyptwy6lm87hf5sspknck9\n",
+		},
+		{
+			"uncompressed key",
+			"0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+			"p2pkh: 1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm\n" +
+				"p2tr: bc1pmfr3p9j00pfxjh0zmgp99y8zftmd3s5pmedqhyptwy6lm87hf5sspknck9\n",
+		},
+	}
+
+	for _, tt := range tests {
+		out, err := runApp(t, "address", "from-pubkey", tt.pubKey)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s: %q != %q", tt.name, out, tt.want)
+		}
+	}
+}
@@ -12,6 +12,7 @@ func main() {
 	app := &cli.App{
 		Name:                   "bitcoin",
 		Usage:                  "toolkit for operations with bitcoin blockchain",
+		Flags:                  commands.GetFlags(),
 		Commands:               commands.GetCommands(),
 		UseShortOptionHandling: true,
 	}
@@ -0,0 +1,210 @@
+package address
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evercoinx/bitcoin/internal/chaincfg"
+	"github.com/evercoinx/bitcoin/internal/encoding"
+)
+
+// Type is a kind of a Bitcoin address.
+type Type int
+
+const (
+	TypeP2PKH Type = iota + 1
+	TypeP2SH
+	TypeP2WPKH
+	TypeP2WSH
+	TypeP2TR
+	// TypeWitnessUnknown is a segwit address of a witness version or
+	// program size without defined semantics yet.
+	TypeWitnessUnknown
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeP2PKH:
+		return "p2pkh"
+	case TypeP2SH:
+		return "p2sh"
+	case TypeP2WPKH:
+		return "p2wpkh"
+	case TypeP2WSH:
+		return "p2wsh"
+	case TypeP2TR:
+		return "p2tr"
+	case TypeWitnessUnknown:
+		return "witness_unknown"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(t))
+	}
+}
+
+// IsSegwit tells whether addresses of the type are Bech32 or Bech32m
+// encoded witness programs.
+func (t Type) IsSegwit() bool {
+	return t >= TypeP2WPKH
+}
+
+const (
+	hashSize              = 20 // in bytes; a hash of a public key or a script
+	taprootKeySize        = 32 // in bytes; an x-only output key
+	witnessScriptHashSize = 32 // in bytes; a SHA-256 of a witness script
+)
+
+// NetworkMismatchError describes an address of a known network decoded for
+// another network.
+type NetworkMismatchError struct {
+	Network  string // of the address
+	Expected string
+}
+
+func (e *NetworkMismatchError) Error() string {
+	return fmt.Sprintf("address: address is for %s, not %s", e.Network, e.Expected)
+}
+
+// Address describes a decoded Bitcoin address.
+type Address struct {
+	Network *chaincfg.Params
+	Type    Type
+	// WitnessVersion is the version of the witness program of a segwit
+	// address and -1 otherwise.
+	WitnessVersion int
+	// Program is the hash of a public key or a script of a Base58Check
+	// address, or the witness program of a segwit address.
+	Program []byte
+}
+
+// String encodes the address. It returns an empty string for an address
+// which cannot be encoded.
+func (a *Address) String() string {
+	switch {
+	case a.Type == TypeP2PKH:
+		return encoding.Base58CheckEncode(a.Program, encoding.AddressVersion(a.Network.PubKeyHashAddrID))
+	case a.Type == TypeP2SH:
+		return encoding.Base58CheckEncode(a.Program, encoding.AddressVersion(a.Network.ScriptHashAddrID))
+	case a.Type.IsSegwit():
+		addr, err := encoding.SegwitAddressEncode(a.Network.Bech32HRP, byte(a.WitnessVersion), a.Program)
+		if err != nil {
+			return ""
+		}
+		return addr
+	default:
+		return ""
+	}
+}
+
+// Parse decodes a Base58Check or a segwit address of any known network.
+//
+// Test networks share address prefixes, so such an address is attributed to
+// the first matching network of chaincfg.Networks, i.e. testnet3, except
+// for regtest segwit addresses which have their own human-readable part.
+func Parse(addr string) (*Address, error) {
+	if params := segwitNetwork(addr); params != nil {
+		return parseSegwit(addr, params)
+	}
+
+	ver, hash, err := encoding.Base58CheckDecodeVersion(addr, 1)
+	if err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+	if len(hash) != hashSize {
+		return nil, fmt.Errorf("address: hash must be %d bytes, got %d", hashSize, len(hash))
+	}
+	for _, params := range chaincfg.Networks {
+		if a, err := newBase58Address(ver[0], hash, params); err == nil {
+			return a, nil
+		}
+	}
+	return nil, fmt.Errorf("address: unknown version 0x%02x", ver[0])
+}
+
+// Decode decodes a Base58Check or a segwit address of the given network. An
+// address of another known network is reported with a *NetworkMismatchError.
+func Decode(addr string, params *chaincfg.Params) (*Address, error) {
+	if addrParams := segwitNetwork(addr); addrParams != nil {
+		if addrParams.Bech32HRP != params.Bech32HRP {
+			return nil, &NetworkMismatchError{Network: addrParams.Name, Expected: params.Name}
+		}
+		return parseSegwit(addr, params)
+	}
+
+	ver, hash, err := encoding.Base58CheckDecodeVersion(addr, 1)
+	if err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+	a, err := newBase58Address(ver[0], hash, params)
+	if err != nil {
+		for _, addrParams := range chaincfg.Networks {
+			if _, perr := newBase58Address(ver[0], hash, addrParams); perr == nil {
+				return nil, &NetworkMismatchError{Network: addrParams.Name, Expected: params.Name}
+			}
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// segwitNetwork returns the network whose human-readable part the address
+// has, or nil if the address is not a segwit address of a known network.
+func segwitNetwork(addr string) *chaincfg.Params {
+	lower := strings.ToLower(addr)
+	sepIdx := strings.LastIndexByte(lower, '1')
+	if sepIdx < 1 {
+		return nil
+	}
+
+	hrp := lower[:sepIdx]
+	for _, params := range chaincfg.Networks {
+		if params.Bech32HRP == hrp {
+			return params
+		}
+	}
+	return nil
+}
+
+func newBase58Address(ver byte, hash []byte, params *chaincfg.Params) (*Address, error) {
+	if len(hash) != hashSize {
+		return nil, fmt.Errorf("address: hash must be %d bytes, got %d", hashSize, len(hash))
+	}
+
+	var typ Type
+	switch ver {
+	case params.PubKeyHashAddrID:
+		typ = TypeP2PKH
+	case params.ScriptHashAddrID:
+		typ = TypeP2SH
+	default:
+		return nil, fmt.Errorf("address: version 0x%02x is not for %s", ver, params.Name)
+	}
+
+	return &Address{
+		Network:        params,
+		Type:           typ,
+		WitnessVersion: -1,
+		Program:        hash,
+	}, nil
+}
+
+func parseSegwit(addr string, params *chaincfg.Params) (*Address, error) {
+	version, program, err := encoding.SegwitAddressDecode(params.Bech32HRP, addr)
+	if err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+
+	return newWitnessAddress(version, program, params), nil
+}
+
+func witnessType(version byte, program []byte) Type {
+	switch {
+	case version == 0 && len(program) == hashSize:
+		return TypeP2WPKH
+	case version == 0 && len(program) == witnessScriptHashSize:
+		return TypeP2WSH
+	case version == 1 && len(program) == taprootKeySize:
+		return TypeP2TR
+	default:
+		return TypeWitnessUnknown
+	}
+}
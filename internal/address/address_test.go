@@ -0,0 +1,216 @@
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/chaincfg"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		address        string
+		network        *chaincfg.Params
+		typ            Type
+		witnessVersion int
+		program        string
+	}{
+		{
+			"mainnet p2pkh",
+			"19g6oo8foQF5jfqK9gH2bLkFNwgCenRBPD",
+			&chaincfg.MainNetParams,
+			TypeP2PKH,
+			-1,
+			"5f2613791b36f667fdb8e95608b55e3df4c5f9eb",
+		},
+		{
+			"mainnet p2sh",
+			"328qTX1KYxMohp4MjPPEDBoRomCGwrB2ag",
+			&chaincfg.MainNetParams,
+			TypeP2SH,
+			-1,
+			"04e214163b3b927c3d2058171dd66ff6780f8708",
+		},
+		{
+			"testnet p2pkh",
+			"mpC46rDecRgLWnJvsFFQRFxaEwGueceNLf",
+			&chaincfg.TestNet3Params,
+			TypeP2PKH,
+			-1,
+			"5f2613791b36f667fdb8e95608b55e3df4c5f9eb",
+		},
+		{
+			"mainnet p2wpkh",
+			"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",
+			&chaincfg.MainNetParams,
+			TypeP2WPKH,
+			0,
+			"751e76e8199196d454941c45d1b3a323f1433bd6",
+		},
+		{
+			"testnet p2wsh",
+			"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7",
+			&chaincfg.TestNet3Params,
+			TypeP2WSH,
+			0,
+			"1863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262",
+		},
+		{
+			"mainnet p2tr",
+			"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+			&chaincfg.MainNetParams,
+			TypeP2TR,
+			1,
+			"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		},
+		{
+			"regtest p2wpkh",
+			"bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080",
+			&chaincfg.RegTestParams,
+			TypeP2WPKH,
+			0,
+			"751e76e8199196d454941c45d1b3a323f1433bd6",
+		},
+		{
+			"witness version 16",
+			"BC1SW50QGDZ25J",
+			&chaincfg.MainNetParams,
+			TypeWitnessUnknown,
+			16,
+			"751e",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Network != tt.network {
+				t.Fatalf("%s != %s", got.Network.Name, tt.network.Name)
+			}
+			if got.Type != tt.typ {
+				t.Fatalf("%s != %s", got.Type, tt.typ)
+			}
+			if got.WitnessVersion != tt.witnessVersion {
+				t.Fatalf("%d != %d", got.WitnessVersion, tt.witnessVersion)
+			}
+			if program := hex.EncodeToString(got.Program); program != tt.program {
+				t.Fatalf("%s != %s", program, tt.program)
+			}
+
+			want := tt.address
+			if tt.typ.IsSegwit() {
+				want = strings.ToLower(want)
+			}
+			if addr := got.String(); addr != want {
+				t.Fatalf("%s != %s", addr, want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		address string
+	}{
+		{
+			"bad base58 checksum",
+			"19g6oo8foQF5jfqK9gH2bLkFNwgCenRBPE",
+		},
+		{
+			"unknown base58 version",
+			"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ",
+		},
+		{
+			"bad bech32 checksum",
+			"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5",
+		},
+		{
+			"unknown human-readable part",
+			"tc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.address); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	const addr = "mpC46rDecRgLWnJvsFFQRFxaEwGueceNLf"
+
+	got, err := Decode(addr, &chaincfg.RegTestParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Network != &chaincfg.RegTestParams || got.Type != TypeP2PKH {
+		t.Fatalf("unexpected address: %s %s", got.Network.Name, got.Type)
+	}
+
+	tests := []struct {
+		name     string
+		address  string
+		params   *chaincfg.Params
+		network  string
+		expected string
+	}{
+		{
+			"base58 address of testnet for mainnet",
+			addr,
+			&chaincfg.MainNetParams,
+			"testnet3",
+			"mainnet",
+		},
+		{
+			"base58 address of mainnet for testnet4",
+			"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+			&chaincfg.TestNet4Params,
+			"mainnet",
+			"testnet4",
+		},
+		{
+			"segwit address of mainnet for testnet4",
+			"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+			&chaincfg.TestNet4Params,
+			"mainnet",
+			"testnet4",
+		},
+		{
+			"segwit address of regtest for mainnet",
+			"BCRT1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KYGT080",
+			&chaincfg.MainNetParams,
+			"regtest",
+			"mainnet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.address, tt.params)
+			var mismatchErr *NetworkMismatchError
+			if !errors.As(err, &mismatchErr) {
+				t.Fatalf("expected network mismatch error, got %v", err)
+			}
+			if mismatchErr.Network != tt.network || mismatchErr.Expected != tt.expected {
+				t.Fatalf("unexpected networks: %s != %s or %s != %s",
+					mismatchErr.Network, tt.network, mismatchErr.Expected, tt.expected)
+			}
+		})
+	}
+}
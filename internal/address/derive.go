@@ -0,0 +1,108 @@
+package address
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/evercoinx/bitcoin/internal/chaincfg"
+	"github.com/evercoinx/bitcoin/internal/crypto"
+	"github.com/evercoinx/bitcoin/internal/hash"
+)
+
+const tagTapTweak = "TapTweak"
+
+var errUncompressedKey = errors.New("address: p2wpkh requires a compressed public key")
+
+// NewP2PKH returns the pay-to-public-key-hash address of a public key in the
+// SEC format. The hash commits to the given form of the key, so a compressed
+// and an uncompressed key give different addresses.
+func NewP2PKH(pubKey []byte, params *chaincfg.Params) (*Address, error) {
+	if _, err := crypto.ParsePublicKey(pubKey); err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+	return newBase58Address(params.PubKeyHashAddrID, hash.Hash160(pubKey), params)
+}
+
+// NewP2WPKH returns the native segwit pay-to-witness-public-key-hash address
+// of a compressed public key.
+func NewP2WPKH(pubKey []byte, params *chaincfg.Params) (*Address, error) {
+	if err := checkCompressed(pubKey); err != nil {
+		return nil, err
+	}
+	return newWitnessAddress(0, hash.Hash160(pubKey), params), nil
+}
+
+// NewP2SHP2WPKH returns the pay-to-script-hash address which nests the
+// P2WPKH witness program of a compressed public key for wallets without
+// segwit support.
+func NewP2SHP2WPKH(pubKey []byte, params *chaincfg.Params) (*Address, error) {
+	if err := checkCompressed(pubKey); err != nil {
+		return nil, err
+	}
+
+	// the redeem script is OP_0 <20-byte hash>
+	redeemScript := append([]byte{0x00, hashSize}, hash.Hash160(pubKey)...)
+	return newBase58Address(params.ScriptHashAddrID, hash.Hash160(redeemScript), params)
+}
+
+// NewP2TR returns the pay-to-taproot address of an internal public key
+// without a script tree. The output key is tweaked according to BIP 341
+// with the hash of the internal key only, as BIP 86 describes. Only the x
+// coordinate of the key is used, so it may be given in any SEC form.
+func NewP2TR(pubKey []byte, params *chaincfg.Params) (*Address, error) {
+	pub, err := crypto.ParsePublicKey(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+
+	// the internal key is taken with an even y
+	internalKey := pub.SerializeXOnly()
+	internal, err := crypto.ParseXOnlyPublicKey(internalKey)
+	if err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+
+	output, err := internal.TweakAdd(hash.TaggedHash(tagTapTweak, internalKey))
+	if err != nil {
+		return nil, fmt.Errorf("address: unable to tweak internal key: %w", err)
+	}
+	return newWitnessAddress(1, output.SerializeXOnly(), params), nil
+}
+
+// NewP2SH returns the pay-to-script-hash address of a redeem script.
+func NewP2SH(script []byte, params *chaincfg.Params) (*Address, error) {
+	if len(script) == 0 {
+		return nil, errors.New("address: script is empty")
+	}
+	return newBase58Address(params.ScriptHashAddrID, hash.Hash160(script), params)
+}
+
+// NewP2WSH returns the native segwit pay-to-witness-script-hash address of a
+// witness script.
+func NewP2WSH(script []byte, params *chaincfg.Params) (*Address, error) {
+	if len(script) == 0 {
+		return nil, errors.New("address: script is empty")
+	}
+	scriptHash := sha256.Sum256(script)
+	return newWitnessAddress(0, scriptHash[:], params), nil
+}
+
+func newWitnessAddress(version byte, program []byte, params *chaincfg.Params) *Address {
+	return &Address{
+		Network:        params,
+		Type:           witnessType(version, program),
+		WitnessVersion: int(version),
+		Program:        program,
+	}
+}
+
+func checkCompressed(pubKey []byte) error {
+	if _, err := crypto.ParsePublicKey(pubKey); err != nil {
+		return fmt.Errorf("address: %w", err)
+	}
+	if len(pubKey) != crypto.PublicKeyCompressedSize {
+		return errUncompressedKey
+	}
+	return nil
+}
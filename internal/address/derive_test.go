@@ -0,0 +1,154 @@
+package address
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/chaincfg"
+)
+
+func TestFromPublicKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		pubKey     string
+		newAddress func([]byte, *chaincfg.Params) (*Address, error)
+		want       string
+	}{
+		{
+			"p2pkh compressed",
+			"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			NewP2PKH,
+			"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		},
+		{
+			"p2pkh uncompressed",
+			"0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+			NewP2PKH,
+			"1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm",
+		},
+		{
+			"p2sh-p2wpkh",
+			"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			NewP2SHP2WPKH,
+			"3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN",
+		},
+		{
+			"p2wpkh",
+			"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			NewP2WPKH,
+			"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		},
+		{
+			"p2tr bip86",
+			"02cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115",
+			NewP2TR,
+			"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr",
+		},
+		{
+			"p2tr odd y internal key",
+			"03cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115",
+			NewP2TR,
+			"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr",
+		},
+		{
+			"p2tr uncompressed internal key",
+			"04cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc1157e6f540ae051df90f5e37da8e812aedc999df252737d4f67f8180d85791a3834",
+			NewP2TR,
+			"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pubKey, err := hex.DecodeString(tt.pubKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tt.newAddress(pubKey, &chaincfg.MainNetParams)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if addr := got.String(); addr != tt.want {
+				t.Fatalf("%s != %s", addr, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromPublicKeyInvalid(t *testing.T) {
+	t.Parallel()
+
+	uncompressed, err := hex.DecodeString("0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewP2WPKH(uncompressed, &chaincfg.MainNetParams); err == nil {
+		t.Fatal("expected error for an uncompressed key")
+	}
+	if _, err := NewP2SHP2WPKH(uncompressed, &chaincfg.MainNetParams); err == nil {
+		t.Fatal("expected error for an uncompressed key")
+	}
+
+	offCurve := make([]byte, 33)
+	offCurve[0] = 0x02
+	offCurve[32] = 0x05
+	if _, err := NewP2PKH(offCurve, &chaincfg.MainNetParams); err == nil {
+		t.Fatal("expected error for a point off the curve")
+	}
+}
+
+func TestFromScript(t *testing.T) {
+	t.Parallel()
+
+	const script = "210279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798ac"
+
+	tests := []struct {
+		name       string
+		network    *chaincfg.Params
+		newAddress func([]byte, *chaincfg.Params) (*Address, error)
+		want       string
+	}{
+		{
+			"p2sh",
+			&chaincfg.MainNetParams,
+			NewP2SH,
+			"34wjDxkCQrUPYwnCRtap5uib6XNcVaud9K",
+		},
+		{
+			"p2wsh",
+			&chaincfg.MainNetParams,
+			NewP2WSH,
+			"bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3",
+		},
+		{
+			"p2wsh testnet",
+			&chaincfg.TestNet3Params,
+			NewP2WSH,
+			"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := hex.DecodeString(script)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tt.newAddress(s, tt.network)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if addr := got.String(); addr != tt.want {
+				t.Fatalf("%s != %s", addr, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewP2WSH(nil, &chaincfg.MainNetParams); err == nil {
+		t.Fatal("expected error for an empty script")
+	}
+}
@@ -0,0 +1,215 @@
+package chaincfg
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const genesisHeaderSize = 80 // in bytes
+
+// mainNetMerkleRoot is the merkle root of the genesis block of every network
+// except testnet4, whose coinbase has its own message.
+const mainNetMerkleRoot = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+
+// GenesisBlock describes the header of the first block of a network and its
+// hash. The merkle root and the hash are hex strings in the byte order used
+// for display, i.e. reversed.
+type GenesisBlock struct {
+	Version    int32
+	MerkleRoot string
+	Timestamp  uint32
+	Bits       uint32
+	Nonce      uint32
+	Hash       string
+}
+
+// Header serializes the 80-byte header of the genesis block, whose double
+// SHA-256 is the block hash.
+func (g *GenesisBlock) Header() []byte {
+	b := make([]byte, genesisHeaderSize)
+	binary.LittleEndian.PutUint32(b[0:4], uint32(g.Version))
+	// the previous block hash at b[4:36] is all zeros
+
+	root, err := hex.DecodeString(g.MerkleRoot)
+	if err != nil {
+		panic(fmt.Sprintf("chaincfg: invalid merkle root %s", g.MerkleRoot))
+	}
+	for i, r := range root {
+		b[67-i] = r
+	}
+
+	binary.LittleEndian.PutUint32(b[68:72], g.Timestamp)
+	binary.LittleEndian.PutUint32(b[72:76], g.Bits)
+	binary.LittleEndian.PutUint32(b[76:80], g.Nonce)
+	return b
+}
+
+// Params describes the parameters of a Bitcoin network.
+type Params struct {
+	Name string
+
+	// Magic starts every P2P message of the network.
+	Magic       [4]byte
+	DefaultPort uint16
+	RPCPort     uint16
+
+	GenesisBlock GenesisBlock
+
+	// version bytes of Base58Check addresses and private keys
+	PubKeyHashAddrID byte
+	ScriptHashAddrID byte
+	PrivateKeyID     byte
+
+	// version bytes of BIP 32 extended keys
+	HDPrivateKeyID [4]byte
+	HDPublicKeyID  [4]byte
+
+	// Bech32HRP is the human-readable part of segwit addresses.
+	Bech32HRP string
+}
+
+var MainNetParams = Params{
+	Name:        "mainnet",
+	Magic:       [4]byte{0xf9, 0xbe, 0xb4, 0xd9},
+	DefaultPort: 8333,
+	RPCPort:     8332,
+	GenesisBlock: GenesisBlock{
+		Version:    1,
+		MerkleRoot: mainNetMerkleRoot,
+		Timestamp:  1231006505,
+		Bits:       0x1d00ffff,
+		Nonce:      2083236893,
+		Hash:       "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f",
+	},
+	PubKeyHashAddrID: 0x00,
+	ScriptHashAddrID: 0x05,
+	PrivateKeyID:     0x80,
+	HDPrivateKeyID:   [4]byte{0x04, 0x88, 0xad, 0xe4}, // xprv
+	HDPublicKeyID:    [4]byte{0x04, 0x88, 0xb2, 0x1e}, // xpub
+	Bech32HRP:        "bc",
+}
+
+var TestNet3Params = Params{
+	Name:        "testnet3",
+	Magic:       [4]byte{0x0b, 0x11, 0x09, 0x07},
+	DefaultPort: 18333,
+	RPCPort:     18332,
+	GenesisBlock: GenesisBlock{
+		Version:    1,
+		MerkleRoot: mainNetMerkleRoot,
+		Timestamp:  1296688602,
+		Bits:       0x1d00ffff,
+		Nonce:      414098458,
+		Hash:       "000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943",
+	},
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+	HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+	Bech32HRP:        "tb",
+}
+
+var TestNet4Params = Params{
+	Name:        "testnet4",
+	Magic:       [4]byte{0x1c, 0x16, 0x3f, 0x28},
+	DefaultPort: 48333,
+	RPCPort:     48332,
+	GenesisBlock: GenesisBlock{
+		Version:    1,
+		MerkleRoot: "7aa0a7ae1e223414cb807e40cd57e667b718e42aaf9306db9102fe28912b7b4e",
+		Timestamp:  1714777860,
+		Bits:       0x1d00ffff,
+		Nonce:      393743547,
+		Hash:       "00000000da84f2bafbbc53dee25a72ae507ff4914b867c565be350b0da8bf043",
+	},
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+	HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+	Bech32HRP:        "tb",
+}
+
+// SigNetParams describes the default signet, whose magic is derived from
+// its challenge script.
+var SigNetParams = Params{
+	Name:        "signet",
+	Magic:       [4]byte{0x0a, 0x03, 0xcf, 0x40},
+	DefaultPort: 38333,
+	RPCPort:     38332,
+	GenesisBlock: GenesisBlock{
+		Version:    1,
+		MerkleRoot: mainNetMerkleRoot,
+		Timestamp:  1598918400,
+		Bits:       0x1e0377ae,
+		Nonce:      52613770,
+		Hash:       "00000008819873e925422c1ff0f99f7cc9bbb232af63a077a480a3633bee1ef6",
+	},
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+	HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+	Bech32HRP:        "tb",
+}
+
+var RegTestParams = Params{
+	Name:        "regtest",
+	Magic:       [4]byte{0xfa, 0xbf, 0xb5, 0xda},
+	DefaultPort: 18444,
+	RPCPort:     18443,
+	GenesisBlock: GenesisBlock{
+		Version:    1,
+		MerkleRoot: mainNetMerkleRoot,
+		Timestamp:  1296688602,
+		Bits:       0x207fffff,
+		Nonce:      2,
+		Hash:       "0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206",
+	},
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+	HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+	Bech32HRP:        "bcrt",
+}
+
+// Networks lists the parameters of all known networks.
+var Networks = []*Params{
+	&MainNetParams,
+	&TestNet3Params,
+	&TestNet4Params,
+	&SigNetParams,
+	&RegTestParams,
+}
+
+// NetworkNames returns the names of all known networks.
+func NetworkNames() []string {
+	names := make([]string, 0, len(Networks))
+	for _, p := range Networks {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// networkAliases maps alternative names to the names of networks.
+var networkAliases = map[string]string{
+	"testnet": TestNet3Params.Name,
+}
+
+// ParamsByName returns the parameters of the network with the given name or
+// its alias, e.g. testnet for testnet3.
+func ParamsByName(name string) (*Params, error) {
+	if alias, ok := networkAliases[name]; ok {
+		name = alias
+	}
+	for _, p := range Networks {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("chaincfg: unknown network %s, expected one of %s", name, strings.Join(NetworkNames(), ", "))
+}
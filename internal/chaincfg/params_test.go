@@ -0,0 +1,90 @@
+package chaincfg
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/hash"
+)
+
+func TestGenesisBlockHash(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range Networks {
+		p := p
+		t.Run(p.Name, func(t *testing.T) {
+			h := hash.Hash256(p.GenesisBlock.Header())
+			for i, j := 0, len(h)-1; i < j; i, j = i+1, j-1 {
+				h[i], h[j] = h[j], h[i]
+			}
+
+			if got := hex.EncodeToString(h); got != p.GenesisBlock.Hash {
+				t.Fatalf("%s != %s", got, p.GenesisBlock.Hash)
+			}
+		})
+	}
+}
+
+func TestParamsByName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		network string
+		want    *Params
+	}{
+		{
+			"mainnet",
+			"mainnet",
+			&MainNetParams,
+		},
+		{
+			"testnet3",
+			"testnet3",
+			&TestNet3Params,
+		},
+		{
+			"testnet4",
+			"testnet4",
+			&TestNet4Params,
+		},
+		{
+			"signet",
+			"signet",
+			&SigNetParams,
+		},
+		{
+			"regtest",
+			"regtest",
+			&RegTestParams,
+		},
+		{
+			"testnet alias",
+			"testnet",
+			&TestNet3Params,
+		},
+		{
+			"unknown network",
+			"testnet5",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParamsByName(tt.network)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("%s != %s", got.Name, tt.want.Name)
+			}
+		})
+	}
+}
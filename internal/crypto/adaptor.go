@@ -0,0 +1,196 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/evercoinx/bitcoin/internal/hash"
+)
+
+// AdaptorSignatureSize is the size of a serialized adaptor signature: the
+// nonce point R in the compressed form followed by s'.
+const AdaptorSignatureSize = PublicKeyCompressedSize + 32
+
+const (
+	tagAdaptorAux   = "SchnorrAdaptor/aux"
+	tagAdaptorNonce = "SchnorrAdaptor/nonce"
+)
+
+// AdaptorSignature describes a Schnorr pre-signature (R, s') locked to an
+// adaptor point T = t*G. It becomes a valid BIP 340 signature (R, s' ± t)
+// only with the secret t, and anyone who sees both signatures learns t. This
+// is the building block of scriptless scripts such as atomic swaps.
+//
+// R = k*G + T is the final nonce point. Its full point is kept since the
+// sign used to apply t depends on the parity of its y: when the y is odd the
+// signer negates k, so the final nonce is -R = -k*G - T with an even y.
+type AdaptorSignature struct {
+	R *PublicKey
+	S *big.Int
+}
+
+// Serialize returns the adaptor signature as 65 bytes: R in the compressed
+// form followed by s'.
+func (sig *AdaptorSignature) Serialize() []byte {
+	b := make([]byte, 0, AdaptorSignatureSize)
+	b = append(b, sig.R.SerializeCompressed()...)
+	return append(b, sig.S.FillBytes(make([]byte, 32))...)
+}
+
+// ParseAdaptorSignature parses a 65-byte adaptor signature and checks that R
+// lies on the curve and s' is less than n.
+func ParseAdaptorSignature(b []byte) (*AdaptorSignature, error) {
+	if len(b) != AdaptorSignatureSize {
+		return nil, fmt.Errorf("adaptor: signature must be %d bytes, got %d", AdaptorSignatureSize, len(b))
+	}
+
+	r, err := ParsePublicKey(b[:PublicKeyCompressedSize])
+	if err != nil {
+		return nil, fmt.Errorf("adaptor: invalid nonce point: %w", err)
+	}
+	s := new(big.Int).SetBytes(b[PublicKeyCompressedSize:])
+	if s.Cmp(secp256k1.Params().N) >= 0 {
+		return nil, errors.New("adaptor: s is not less than the curve order")
+	}
+	return &AdaptorSignature{R: r, S: s}, nil
+}
+
+// AdaptorSign creates an adaptor signature of the message with the private
+// key which is locked to the adaptor point.
+//
+// Like in SchnorrSign the nonce is derived from the key, the message and 32
+// bytes of auxiliary random data. The adaptor point is committed to as well,
+// so pre-signatures for different adaptors never share a nonce.
+func AdaptorSign(key *PrivateKey, msg []byte, adaptor *PublicKey, auxRand []byte) (*AdaptorSignature, error) {
+	if key == nil || !isValidScalar(key.D) {
+		return nil, ErrInvalidPrivateKey
+	}
+	if adaptor == nil || !adaptor.isValid() {
+		return nil, fmt.Errorf("adaptor: invalid adaptor point: %w", ErrInvalidPublicKey)
+	}
+	if len(auxRand) != 32 {
+		return nil, fmt.Errorf("adaptor: auxiliary random data must be 32 bytes, got %d", len(auxRand))
+	}
+
+	d := evenYScalar(key.D, key.Y)
+	px := key.SerializeXOnly()
+
+	// t = bytes(d) xor hash_aux(a)
+	t := d.FillBytes(make([]byte, 32))
+	for i, b := range hash.TaggedHash(tagAdaptorAux, auxRand) {
+		t[i] ^= b
+	}
+
+	var k scalarVal
+	k.setByteSlice(hash.TaggedHash(tagAdaptorNonce, t, adaptor.SerializeCompressed(), px, msg))
+	if k.isZero() == 1 {
+		return nil, errors.New("adaptor: nonce is zero")
+	}
+
+	// R = k*G + T
+	kb := k.bytes()
+	x, y := secp256k1.ScalarBaseMult(kb[:])
+	rx, ry := secp256k1.Add(x, y, adaptor.X, adaptor.Y)
+	if rx == nil {
+		return nil, errors.New("adaptor: nonce point is at infinity")
+	}
+	if ry.Bit(0) == 1 {
+		k.neg(&k)
+	}
+
+	// s' = k + ed % n
+	var ds, es, s scalarVal
+	ds.setBig(d)
+	es.setBig(schnorrChallenge(rx, key.X, msg))
+	s.mul(&es, &ds)
+	s.add(&s, &k)
+
+	sig := &AdaptorSignature{R: &PublicKey{X: rx, Y: ry}, S: s.big()}
+	if !AdaptorVerify(key.Public(), msg, adaptor, sig) {
+		return nil, errors.New("adaptor: produced signature is invalid")
+	}
+	return sig, nil
+}
+
+// AdaptorVerify reports whether the adaptor signature of the message is
+// valid for the public key and the adaptor point, that is whether completing
+// it with the discrete logarithm of the adaptor point yields a valid BIP 340
+// signature. Only the x coordinate of the public key is used.
+func AdaptorVerify(pub *PublicKey, msg []byte, adaptor *PublicKey, sig *AdaptorSignature) bool {
+	if pub == nil || pub.X == nil || adaptor == nil || !adaptor.isValid() {
+		return false
+	}
+	if sig == nil || sig.R == nil || !sig.R.isValid() || sig.S == nil {
+		return false
+	}
+	if sig.S.Sign() < 0 || sig.S.Cmp(secp256k1.params.N) >= 0 {
+		return false
+	}
+
+	p, err := liftX(pub.X)
+	if err != nil {
+		return false
+	}
+
+	// s'*G - e*P = R - T, negated if R has an odd y
+	e := schnorrChallenge(sig.R.X, p.X, msg)
+	e.Sub(secp256k1.params.N, e)
+	x1, y1 := secp256k1.ScalarBaseMult(sig.S.Bytes())
+	x2, y2 := secp256k1.ScalarMult(p.X, p.Y, e.Bytes())
+	x, y := secp256k1.Add(x1, y1, x2, y2)
+
+	ty := new(big.Int).Sub(secp256k1.params.P, adaptor.Y)
+	wantX, wantY := secp256k1.Add(sig.R.X, sig.R.Y, adaptor.X, ty)
+	if wantX == nil || x == nil {
+		return false
+	}
+	if sig.R.Y.Bit(0) == 1 {
+		wantY.Sub(secp256k1.params.P, wantY)
+	}
+	return x.Cmp(wantX) == 0 && y.Cmp(wantY) == 0
+}
+
+// Complete turns the adaptor signature into a BIP 340 signature using the
+// secret t of the adaptor point T = t*G.
+func (sig *AdaptorSignature) Complete(secret *PrivateKey) (*SchnorrSignature, error) {
+	if secret == nil || !isValidScalar(secret.D) {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	// s = s' + t, or s' - t if R has an odd y
+	var s, t scalarVal
+	s.setBig(sig.S)
+	t.setBig(secret.D)
+	if sig.R.Y.Bit(0) == 1 {
+		t.neg(&t)
+	}
+	s.add(&s, &t)
+	return &SchnorrSignature{R: new(big.Int).Set(sig.R.X), S: s.big()}, nil
+}
+
+// ExtractSecret recovers the secret t of the adaptor point from the adaptor
+// signature and the BIP 340 signature completed from it.
+func (sig *AdaptorSignature) ExtractSecret(final *SchnorrSignature, adaptor *PublicKey) (*PrivateKey, error) {
+	if final == nil || final.R == nil || final.S == nil || final.R.Cmp(sig.R.X) != 0 {
+		return nil, errors.New("adaptor: signature is not completed from the adaptor signature")
+	}
+
+	// t = s - s', or s' - s if R has an odd y
+	var s, sp, t scalarVal
+	s.setBig(final.S)
+	sp.setBig(sig.S)
+	t.sub(&s, &sp)
+	if sig.R.Y.Bit(0) == 1 {
+		t.neg(&t)
+	}
+
+	secret, err := NewPrivateKeyFromInt(t.big())
+	if err != nil {
+		return nil, errors.New("adaptor: extracted secret is zero")
+	}
+	if adaptor == nil || !secret.Public().IsEqual(adaptor) {
+		return nil, errors.New("adaptor: extracted secret does not match the adaptor point")
+	}
+	return secret, nil
+}
@@ -0,0 +1,161 @@
+package crypto_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+)
+
+func TestAdaptorSign(t *testing.T) {
+	t.Parallel()
+
+	const (
+		key = "b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfef"
+		msg = "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89"
+		aux = "0000000000000000000000000000000000000000000000000000000000000001"
+	)
+
+	tests := []struct {
+		name      string
+		secret    string
+		adaptor   string
+		wantPre   string
+		wantFinal string
+	}{
+		{
+			name:      "even nonce point",
+			secret:    "3",
+			adaptor:   "02f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9",
+			wantPre:   "02f091f493c64591cb1ed88d4fb67081035337298a6762b225389bba17a59cd749ff01151429815d5d239c7c431a23dc197eea13ec4d914d16e645894006601009",
+			wantFinal: "f091f493c64591cb1ed88d4fb67081035337298a6762b225389bba17a59cd749ff01151429815d5d239c7c431a23dc197eea13ec4d914d16e64589400660100c",
+		},
+		{
+			name:      "odd nonce point",
+			secret:    "b432b2677937381aef05bb02a66ecd012773062cf3fa2549e44f58ed2401710",
+			adaptor:   "0325d1dff95105f5253c4022f628a996ad3a0d95fbf21d468a1b33f8c160d8f517",
+			wantPre:   "0351aa4c33e69c92e11c6bccdd2ff7d784262a69941e679eb2eb1017ac0e9f7b0670238b5c5c903cfe6d6b05c283df0bc666fe1edc377426db04d1814a01e87ab8",
+			wantFinal: "51aa4c33e69c92e11c6bccdd2ff7d784262a69941e679eb2eb1017ac0e9f7b0664e06035e4fcc97cbe7aaa1259781ef65486ee7968348486668c8bbb2fa863a8",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			priv, err := crypto.NewPrivateKey(mustDecodeHex(t, key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			d, _ := new(big.Int).SetString(tt.secret, 16)
+			secret, err := crypto.NewPrivateKeyFromInt(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			adaptor, err := crypto.ParsePublicKey(mustDecodeHex(t, tt.adaptor))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !secret.Public().IsEqual(adaptor) {
+				t.Fatal("adaptor point does not match the secret")
+			}
+			m := mustDecodeHex(t, msg)
+
+			pre, err := crypto.AdaptorSign(priv, m, adaptor, mustDecodeHex(t, aux))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(pre.Serialize()); got != tt.wantPre {
+				t.Fatalf("adaptor signature: %s != %s", got, tt.wantPre)
+			}
+			if !crypto.AdaptorVerify(priv.Public(), m, adaptor, pre) {
+				t.Fatal("adaptor signature is invalid")
+			}
+
+			// the pre-signature alone is not a valid signature
+			if crypto.SchnorrVerify(priv.Public(), m, &crypto.SchnorrSignature{R: pre.R.X, S: pre.S}) {
+				t.Fatal("adaptor signature verifies as a Schnorr signature")
+			}
+
+			final, err := pre.Complete(secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(final.Serialize()); got != tt.wantFinal {
+				t.Fatalf("signature: %s != %s", got, tt.wantFinal)
+			}
+			if !crypto.SchnorrVerify(priv.Public(), m, final) {
+				t.Fatal("completed signature is invalid")
+			}
+
+			extracted, err := pre.ExtractSecret(final, adaptor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(extracted.Bytes(), secret.Bytes()) {
+				t.Fatalf("%x != %x", extracted.Bytes(), secret.Bytes())
+			}
+		})
+	}
+}
+
+func TestAdaptorVerifyInvalid(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GeneratePrivateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := crypto.GeneratePrivateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GeneratePrivateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("swap 1 BTC for 100 LTC")
+	pre, err := crypto.AdaptorSign(priv, msg, secret.Public(), make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := crypto.ParseAdaptorSignature(pre.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !crypto.AdaptorVerify(priv.Public(), msg, secret.Public(), parsed) {
+		t.Fatal("parsed adaptor signature is invalid")
+	}
+
+	if crypto.AdaptorVerify(priv.Public(), msg, other.Public(), pre) {
+		t.Fatal("adaptor signature is valid for another adaptor point")
+	}
+	if crypto.AdaptorVerify(priv.Public(), []byte("swap 1 BTC for 1 LTC"), secret.Public(), pre) {
+		t.Fatal("adaptor signature is valid for another message")
+	}
+	if crypto.AdaptorVerify(other.Public(), msg, secret.Public(), pre) {
+		t.Fatal("adaptor signature is valid for another public key")
+	}
+
+	// completing with the wrong secret gives an invalid signature
+	final, err := pre.Complete(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.SchnorrVerify(priv.Public(), msg, final) {
+		t.Fatal("signature completed with a wrong secret is valid")
+	}
+	if _, err := pre.ExtractSecret(final, secret.Public()); err == nil {
+		t.Fatal("expected error for a wrong secret")
+	}
+
+	if _, err := crypto.ParseAdaptorSignature(pre.Serialize()[1:]); err == nil {
+		t.Fatal("expected error for a short signature")
+	}
+}
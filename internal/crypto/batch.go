@@ -0,0 +1,115 @@
+package crypto
+
+import (
+	"encoding/binary"
+
+	"github.com/evercoinx/bitcoin/internal/hash"
+)
+
+const tagBIP340Batch = "BIP0340/batch"
+
+// SchnorrBatchEntry describes a BIP 340 signature of a message to be
+// verified in a batch.
+type SchnorrBatchEntry struct {
+	PublicKey *PublicKey
+	Message   []byte
+	Signature *SchnorrSignature
+}
+
+// SchnorrVerifyBatch reports whether all BIP 340 signatures in the batch are
+// valid. If they are not, it also returns the index of the first invalid
+// entry; otherwise the index is -1. An empty batch is valid.
+//
+// The batch is verified at once according to BIP 340: the equations
+// s_i*G = R_i + e_i*P_i are combined with random factors a_i (a_0 = 1) into
+// (a_0*s_0 + ... + a_u*s_u)*G = a_0*R_0 + a_0*e_0*P_0 + ... + a_u*e_u*P_u
+// which is checked with a single multi-scalar multiplication using
+// Pippenger's method, whose cost per signature shrinks as the batch grows.
+// The factors are derived from a hash of all the entries, so an attacker
+// cannot choose invalid signatures which cancel each other out. Only when
+// the combined equation fails, the entries are verified one by one to find
+// the culprit.
+func SchnorrVerifyBatch(entries []SchnorrBatchEntry) (bool, int) {
+	if len(entries) == 0 {
+		return true, -1
+	}
+
+	pubs := make([]*PublicKey, len(entries))
+	nonces := make([]*PublicKey, len(entries))
+	for i, e := range entries {
+		if !isValidSchnorrInput(e.PublicKey, e.Signature) {
+			return false, i
+		}
+
+		var err error
+		if pubs[i], err = liftX(e.PublicKey.X); err != nil {
+			return false, i
+		}
+		if nonces[i], err = liftX(e.Signature.R); err != nil {
+			return false, i
+		}
+	}
+
+	seed := schnorrBatchSeed(entries)
+
+	// sum*G - (a_0*R_0 + a_0*e_0*P_0 + ...) = 0
+	points := make([]affinePoint, 2*len(entries)+1)
+	scalars := make([]scalarVal, 2*len(entries)+1)
+	var sum scalarVal
+	for i, e := range entries {
+		var a, s, c scalarVal
+		if i == 0 {
+			a.setInt(1)
+		} else {
+			var idx [4]byte
+			binary.BigEndian.PutUint32(idx[:], uint32(i))
+			a.setByteSlice(hash.TaggedHash(tagBIP340Batch, seed, idx[:]))
+			if a.isZero() == 1 {
+				a.setInt(1)
+			}
+		}
+		s.setBig(e.Signature.S)
+		c.setBig(schnorrChallenge(nonces[i].X, pubs[i].X, e.Message))
+
+		// sum += a*s
+		s.mul(&s, &a)
+		sum.add(&sum, &s)
+
+		points[2*i].x.setBig(nonces[i].X)
+		points[2*i].y.setBig(nonces[i].Y)
+		scalars[2*i].neg(&a)
+		points[2*i+1].x.setBig(pubs[i].X)
+		points[2*i+1].y.setBig(pubs[i].Y)
+		scalars[2*i+1].mul(&a, &c)
+		scalars[2*i+1].neg(&scalars[2*i+1])
+	}
+
+	n := len(entries)
+	points[2*n].x.setBig(secp256k1.params.Gx)
+	points[2*n].y.setBig(secp256k1.params.Gy)
+	scalars[2*n] = sum
+
+	var res jacobianPoint
+	if res.mulMultiAffine(points, scalars).isInfinity() {
+		return true, -1
+	}
+
+	for i, e := range entries {
+		if !SchnorrVerify(e.PublicKey, e.Message, e.Signature) {
+			return false, i
+		}
+	}
+	// unreachable unless the combined equation is wrong
+	return false, -1
+}
+
+// schnorrBatchSeed returns a hash committing to all entries of the batch.
+func schnorrBatchSeed(entries []SchnorrBatchEntry) []byte {
+	data := make([][]byte, 0, 3*len(entries))
+	for _, e := range entries {
+		var size [8]byte
+		binary.BigEndian.PutUint64(size[:], uint64(len(e.Message)))
+		data = append(data, e.PublicKey.SerializeXOnly(), e.Signature.Serialize(), size[:], e.Message)
+	}
+	return hash.TaggedHash(tagBIP340Batch, data...)
+}
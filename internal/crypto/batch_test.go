@@ -0,0 +1,186 @@
+package crypto_test
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+)
+
+func randomSchnorrBatch(t testing.TB, size int) []crypto.SchnorrBatchEntry {
+	t.Helper()
+
+	entries := make([]crypto.SchnorrBatchEntry, size)
+	for i := range entries {
+		key, err := crypto.GeneratePrivateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		msg := []byte(fmt.Sprintf("message %d", i))
+		sig, err := crypto.SchnorrSign(key, msg, make([]byte, 32))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[i] = crypto.SchnorrBatchEntry{PublicKey: key.Public(), Message: msg, Signature: sig}
+	}
+	return entries
+}
+
+func TestSchnorrVerifyBatch(t *testing.T) {
+	t.Parallel()
+
+	const size = 16
+	tests := []struct {
+		name       string
+		corrupt    func(e *crypto.SchnorrBatchEntry)
+		index      int
+		wantFailed int
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "wrong message",
+			corrupt: func(e *crypto.SchnorrBatchEntry) {
+				e.Message = []byte("forged")
+			},
+			index:      5,
+			wantFailed: 5,
+		},
+		{
+			name: "first signature wrong",
+			corrupt: func(e *crypto.SchnorrBatchEntry) {
+				e.Signature = &crypto.SchnorrSignature{R: e.Signature.R, S: new(big.Int).Add(e.Signature.S, big.NewInt(1))}
+			},
+			index:      0,
+			wantFailed: 0,
+		},
+		{
+			name: "r not on the curve",
+			corrupt: func(e *crypto.SchnorrBatchEntry) {
+				e.Signature = &crypto.SchnorrSignature{R: big.NewInt(5), S: e.Signature.S}
+			},
+			index:      size - 1,
+			wantFailed: size - 1,
+		},
+		{
+			name: "s out of range",
+			corrupt: func(e *crypto.SchnorrBatchEntry) {
+				e.Signature = &crypto.SchnorrSignature{R: e.Signature.R, S: crypto.Secp256k1().Params().N}
+			},
+			index:      9,
+			wantFailed: 9,
+		},
+		{
+			name: "missing public key",
+			corrupt: func(e *crypto.SchnorrBatchEntry) {
+				e.PublicKey = nil
+			},
+			index:      2,
+			wantFailed: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entries := randomSchnorrBatch(t, size)
+			want := true
+			wantFailed := -1
+			if tt.corrupt != nil {
+				tt.corrupt(&entries[tt.index])
+				want = false
+				wantFailed = tt.wantFailed
+			}
+
+			got, failed := crypto.SchnorrVerifyBatch(entries)
+			if got != want || failed != wantFailed {
+				t.Fatalf("(%t, %d) != (%t, %d)", got, failed, want, wantFailed)
+			}
+		})
+	}
+}
+
+func TestSchnorrVerifyBatchEmpty(t *testing.T) {
+	t.Parallel()
+
+	if ok, failed := crypto.SchnorrVerifyBatch(nil); !ok || failed != -1 {
+		t.Fatalf("(%t, %d) != (true, -1)", ok, failed)
+	}
+}
+
+func TestSchnorrVerifyBatchBIP340Vectors(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("testdata/bip340_test_vectors.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var valid []crypto.SchnorrBatchEntry
+	var invalid []string
+	for _, rec := range records[1:] {
+		pub, err := crypto.ParseXOnlyPublicKey(mustDecodeHex(t, rec[2]))
+		if err != nil {
+			continue
+		}
+		sig, err := crypto.ParseSchnorrSignature(mustDecodeHex(t, rec[5]))
+		if err != nil {
+			continue
+		}
+
+		e := crypto.SchnorrBatchEntry{PublicKey: pub, Message: mustDecodeHex(t, rec[4]), Signature: sig}
+		if rec[6] == "TRUE" {
+			valid = append(valid, e)
+			continue
+		}
+
+		// put every invalid signature in the middle of the valid ones
+		batch := append(append(append([]crypto.SchnorrBatchEntry{}, valid...), e), valid...)
+		if ok, failed := crypto.SchnorrVerifyBatch(batch); ok || failed != len(valid) {
+			t.Fatalf("vector %s: (%t, %d) != (false, %d)", rec[0], ok, failed, len(valid))
+		}
+		invalid = append(invalid, rec[0])
+	}
+
+	if ok, failed := crypto.SchnorrVerifyBatch(valid); !ok || failed != -1 {
+		t.Fatalf("(%t, %d) != (true, -1)", ok, failed)
+	}
+	if len(valid) == 0 || len(invalid) == 0 {
+		t.Fatalf("%d valid and %d invalid vectors", len(valid), len(invalid))
+	}
+}
+
+func BenchmarkSchnorrVerifyBatch(b *testing.B) {
+	// a block holds a few thousand signatures
+	for _, size := range []int{64, 1000, 4000} {
+		entries := randomSchnorrBatch(b, size)
+
+		b.Run(fmt.Sprintf("batch of %d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				crypto.SchnorrVerifyBatch(entries)
+			}
+		})
+
+		b.Run(fmt.Sprintf("one by one %d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, e := range entries {
+					crypto.SchnorrVerify(e.PublicKey, e.Message, e.Signature)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/evercoinx/bitcoin/internal/hash"
+)
+
+// CompactSignatureSize is the size of a compact recoverable signature: a
+// 1-byte header followed by 32-byte r and s.
+const CompactSignatureSize = 65
+
+const (
+	// compactHeaderBase is the header of a signature with the recovery id 0
+	// made by a key with an uncompressed public key.
+	compactHeaderBase = 27
+
+	// compactHeaderCompressed is added to the header when the public key is
+	// serialized in the compressed form.
+	compactHeaderCompressed = 4
+
+	messageMagic = "Bitcoin Signed Message:\n"
+)
+
+// SignCompact signs a message hash with the private key and returns the
+// signature in the 65-byte compact form used by the signmessage RPC of
+// Bitcoin Core:
+//
+//	<27 + recovery id + 4 if compressed> <r> <s>
+//
+// The compressed flag tells verifiers which serialization of the recovered
+// public key was used by the signer.
+func SignCompact(key *PrivateKey, hash []byte, compressed bool) ([]byte, error) {
+	sig, recID, err := sign(key, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, CompactSignatureSize)
+	b[0] = compactHeaderBase + recID
+	if compressed {
+		b[0] += compactHeaderCompressed
+	}
+	sig.R.FillBytes(b[1:33])
+	sig.S.FillBytes(b[33:])
+	return b, nil
+}
+
+// RecoverPublicKey recovers the public key of the signer from a compact
+// signature of the message hash. It also reports whether the signer used the
+// compressed form of the public key.
+func RecoverPublicKey(hash, sig []byte) (*PublicKey, bool, error) {
+	if len(sig) != CompactSignatureSize {
+		return nil, false, fmt.Errorf("crypto: compact signature must be %d bytes, got %d", CompactSignatureSize, len(sig))
+	}
+
+	header := sig[0]
+	if header < compactHeaderBase || header >= compactHeaderBase+2*compactHeaderCompressed {
+		return nil, false, fmt.Errorf("crypto: invalid compact signature header %d", header)
+	}
+
+	recID := header - compactHeaderBase
+	compressed := recID >= compactHeaderCompressed
+	if compressed {
+		recID -= compactHeaderCompressed
+	}
+
+	pub, err := recoverPublicKey(hash, &Signature{
+		R: new(big.Int).SetBytes(sig[1:33]),
+		S: new(big.Int).SetBytes(sig[33:]),
+	}, recID)
+	if err != nil {
+		return nil, false, err
+	}
+	return pub, compressed, nil
+}
+
+// recoverPublicKey computes the public key Q = r^-1(sR - eG) where R is the
+// nonce point restored from r and the recovery id.
+func recoverPublicKey(hash []byte, sig *Signature, recID byte) (*PublicKey, error) {
+	params := secp256k1.Params()
+	n := params.N
+	if !isValidScalar(sig.R) || !isValidScalar(sig.S) {
+		return nil, errors.New("crypto: signature values are out of range [1, n-1]")
+	}
+
+	rx := new(big.Int).Set(sig.R)
+	if recID&2 != 0 {
+		rx.Add(rx, n)
+		if rx.Cmp(params.P) >= 0 {
+			return nil, errors.New("crypto: nonce point x overflows the field size")
+		}
+	}
+
+	ry, err := decompressY(rx, recID&1 == 1)
+	if err != nil {
+		return nil, errors.New("crypto: nonce point is not on the curve")
+	}
+
+	rInv := new(big.Int).ModInverse(sig.R, n)
+	u1 := new(big.Int).Mul(hashToInt(hash), rInv)
+	u1.Sub(n, u1.Mod(u1, n))
+	u2 := new(big.Int).Mul(sig.S, rInv)
+	u2.Mod(u2, n)
+
+	x1, y1 := secp256k1.ScalarBaseMult(u1.Bytes())
+	x2, y2 := secp256k1.ScalarMult(rx, ry, u2.Bytes())
+	x, y := secp256k1.Add(x1, y1, x2, y2)
+	if x == nil {
+		return nil, errors.New("crypto: recovered public key is the point at infinity")
+	}
+	return &PublicKey{X: x, Y: y}, nil
+}
+
+// MessageHash returns the double SHA-256 of a message prefixed with the
+// magic string used by the signmessage and verifymessage RPCs of Bitcoin
+// Core.
+func MessageHash(msg []byte) []byte {
+	var data []byte
+	data = appendCompactSize(data, uint64(len(messageMagic)))
+	data = append(data, messageMagic...)
+	data = appendCompactSize(data, uint64(len(msg)))
+	data = append(data, msg...)
+	return hash.Hash256(data)
+}
+
+// appendCompactSize appends a variable length integer in the compact size
+// form of the Bitcoin protocol.
+func appendCompactSize(b []byte, n uint64) []byte {
+	switch {
+	case n < 0xfd:
+		return append(b, byte(n))
+	case n <= 0xffff:
+		return append(b, 0xfd, byte(n), byte(n>>8))
+	case n <= 0xffffffff:
+		return append(b, 0xfe, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	default:
+		return append(b, 0xff, byte(n), byte(n>>8), byte(n>>16), byte(n>>24),
+			byte(n>>32), byte(n>>40), byte(n>>48), byte(n>>56))
+	}
+}
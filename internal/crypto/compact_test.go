@@ -0,0 +1,203 @@
+package crypto_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/address"
+	"github.com/evercoinx/bitcoin/internal/chaincfg"
+	"github.com/evercoinx/bitcoin/internal/crypto"
+)
+
+func TestSignCompact(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		key        string
+		message    string
+		compressed bool
+		want       string
+	}{
+		{
+			name:       "compressed key",
+			key:        "1",
+			message:    "Hello, world!",
+			compressed: true,
+			want:       "1fed07cfd4d0f36ec7b0751a4fee06ec5049eacb103b3668484d93df68f15269e352ef4dcc55729cbf6fd42fbe9ebe08c214f9297de225c3511a08c08dda8abc8b",
+		},
+		{
+			name:       "uncompressed key",
+			key:        "5cd",
+			message:    "Hello, world!",
+			compressed: false,
+			want:       "1bc693e37b631bd030c8f110c4291d9a1a25b801f0d5bf248762554196d3569bce3a4b3ea44d21424bf8cecc208acfa604a29c694842f09c63a067f906f8db6d5a",
+		},
+		{
+			name:       "odd nonce point",
+			key:        "f8b8af8ce3c7cca5e300d33939540c10d45ce001b8f252bfbc57ba0342904181",
+			message:    "Alan Turing",
+			compressed: true,
+			want:       "206f059f5aff67a8376b28d31cd7bcfbd036bdc12b9fac48909625bc609e652e250a2f12631580e49fc98b84817c45ff7935d204dd83aeb1c856541757fc263a1c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := new(big.Int).SetString(tt.key, 16)
+			key, err := crypto.NewPrivateKeyFromInt(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hash := crypto.MessageHash([]byte(tt.message))
+			sig, err := crypto.SignCompact(key, hash, tt.compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(sig); got != tt.want {
+				t.Fatalf("%s != %s", got, tt.want)
+			}
+
+			pub, compressed, err := crypto.RecoverPublicKey(hash, sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pub.IsEqual(key.Public()) {
+				t.Fatalf("(%x,%x) != (%x,%x)", pub.X, pub.Y, key.X, key.Y)
+			}
+			if compressed != tt.compressed {
+				t.Fatalf("%t != %t", compressed, tt.compressed)
+			}
+		})
+	}
+}
+
+func TestRecoverPublicKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.NewPrivateKeyFromInt(big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := crypto.MessageHash([]byte("Hello, world!"))
+	sig, err := crypto.SignCompact(key, hash, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("another hash", func(t *testing.T) {
+		pub, _, err := crypto.RecoverPublicKey(crypto.MessageHash([]byte("Hello, world?")), sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pub.IsEqual(key.Public()) {
+			t.Fatal("recovered the signer for another hash")
+		}
+	})
+
+	tests := []struct {
+		name string
+		sig  []byte
+	}{
+		{
+			name: "short signature",
+			sig:  sig[:64],
+		},
+		{
+			name: "header too low",
+			sig:  append([]byte{26}, sig[1:]...),
+		},
+		{
+			name: "header too high",
+			sig:  append([]byte{35}, sig[1:]...),
+		},
+		{
+			name: "zero r",
+			sig:  append(append([]byte{31}, make([]byte, 32)...), sig[33:]...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := crypto.RecoverPublicKey(hash, tt.sig); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestRecoverPublicKeySignMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		address   string
+		message   string
+		signature string
+		params    *chaincfg.Params
+	}{
+		{
+			// test/functional/rpc_signmessage.py of Bitcoin Core
+			name:      "compressed key",
+			address:   "mpLQjfK79b7CCV4VMJWEWAj5Mpx8Up5zxB",
+			message:   "This is just a test message",
+			signature: "INbVnW4e6PeRmsv2Qgu8NuopvrVjkcxob+sX8OcZG0SALhWybUjzMLPdAsXI46YZGb0KQTRii+wWIQzRpG/U+S0=",
+			params:    &chaincfg.TestNet3Params,
+		},
+		{
+			name:      "uncompressed key",
+			address:   "1HZwkjkeaoZfTSaJxDw6aKkxp45agDiEzN",
+			message:   "This is an example of a signed message.",
+			signature: "HJLQlDWLyb1Ef8bQKEISzFbDAKctIlaqOpGbrk3YVtRsjmC61lpE5ErkPRUFtDKtx98vHFGUWlFhsh3DiW6N0rE=",
+			params:    &chaincfg.MainNetParams,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sig, err := base64.StdEncoding.DecodeString(tt.signature)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pub, compressed, err := crypto.RecoverPublicKey(crypto.MessageHash([]byte(tt.message)), sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pubKey := pub.SerializeUncompressed()
+			if compressed {
+				pubKey = pub.SerializeCompressed()
+			}
+			addr, err := address.NewP2PKH(pubKey, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := addr.String(); got != tt.address {
+				t.Fatalf("%s != %s", got, tt.address)
+			}
+		})
+	}
+}
+
+func TestMessageHash(t *testing.T) {
+	t.Parallel()
+
+	want, err := hex.DecodeString("02d6c0643e40b0db549cbbd7eb47dcab71a59d7017199ebde6b272f28fbbf95f")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := crypto.MessageHash([]byte("Hello, world!"))
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%x != %x", got, want)
+	}
+}
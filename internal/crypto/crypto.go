@@ -3,14 +3,17 @@ package crypto
 import (
 	"crypto/elliptic"
 	"math/big"
-
-	"github.com/evercoinx/kit/crypto"
 )
 
-var secp256k1 elliptic.Curve
+var (
+	secp256k1 *koblitzCurve
+
+	// halfOrder is n/2 which separates low and high values of s in ECDSA
+	// signatures.
+	halfOrder *big.Int
+)
 
 func init() {
-	a := big.NewInt(0)
 	b := big.NewInt(7)
 	p, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
 	n, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
@@ -19,7 +22,16 @@ func init() {
 	bitSize := 256
 	name := "Secp256k1"
 
-	secp256k1 = crypto.NewEllipticCurve(a, b, p, n, gx, gy, bitSize, name)
+	secp256k1 = newKoblitzCurve(&elliptic.CurveParams{
+		P:       p,
+		N:       n,
+		B:       b,
+		Gx:      gx,
+		Gy:      gy,
+		BitSize: bitSize,
+		Name:    name,
+	})
+	halfOrder = new(big.Int).Rsh(n, 1)
 }
 
 func Secp256k1() elliptic.Curve {
@@ -0,0 +1,118 @@
+package crypto
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// koblitzCurve describes the secp256k1 curve y^2 = x^3 + 7 over the field of
+// integers modulo p.
+//
+// Points are handled in Jacobian coordinates over fixed-width field elements,
+// so additions and doublings need neither modular inversions nor big.Int
+// allocations. A single inversion is performed when the result is converted
+// back to affine coordinates. The point at infinity is represented by nil
+// coordinates in affine form.
+type koblitzCurve struct {
+	params *elliptic.CurveParams
+}
+
+func newKoblitzCurve(params *elliptic.CurveParams) *koblitzCurve {
+	return &koblitzCurve{params: params}
+}
+
+func (c *koblitzCurve) Params() *elliptic.CurveParams {
+	return c.params
+}
+
+// IsOnCurve reports whether the given point (x,y) lies on the curve.
+func (c *koblitzCurve) IsOnCurve(x, y *big.Int) bool {
+	// (x,y) is the point at infinity
+	if x == nil {
+		return true
+	}
+	if y == nil || !c.isFieldElement(x) || !c.isFieldElement(y) {
+		return false
+	}
+
+	var fx, fy fieldVal
+	fx.setBig(x)
+	fy.setBig(y)
+	return isOnCurve(&fx, &fy)
+}
+
+func (c *koblitzCurve) isFieldElement(v *big.Int) bool {
+	return v.Sign() >= 0 && v.Cmp(c.params.P) < 0
+}
+
+// isOnCurve reports whether y^2 = x^3 + 7.
+func isOnCurve(x, y *fieldVal) bool {
+	var lhs, rhs, b fieldVal
+	lhs.sqr(y)
+	rhs.sqr(x)
+	rhs.mul(&rhs, x)
+	rhs.add(&rhs, b.setInt(7))
+	return lhs.equal(&rhs) == 1
+}
+
+// Add returns the sum of points (x1,y1) and (x2,y2).
+func (c *koblitzCurve) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	var p1, p2 jacobianPoint
+	p1.setAffine(x1, y1)
+	p2.setAffine(x2, y2)
+	return p1.add(&p1, &p2).toAffine()
+}
+
+// Double returns 2*(x,y).
+func (c *koblitzCurve) Double(x1, y1 *big.Int) (x, y *big.Int) {
+	var p jacobianPoint
+	p.setAffine(x1, y1)
+	return p.double(&p).toAffine()
+}
+
+// ScalarMult returns k*(Bx,By) where k is a number in big-endian form.
+//
+// The GLV endomorphism is used to halve the number of doublings. The running
+// time depends on k, so it must only be used with public scalars, e.g. for
+// signature verification.
+func (c *koblitzCurve) ScalarMult(x1, y1 *big.Int, k []byte) (x, y *big.Int) {
+	// (x1,y1) is the point at infinity
+	if x1 == nil {
+		return
+	}
+
+	var p jacobianPoint
+	var s scalarVal
+	p.setAffine(x1, y1)
+	// we can reduce k to fit the order of the cyclic group
+	s.setByteSlice(k)
+	return p.mulGLV(&p, &s).toAffine()
+}
+
+// ScalarBaseMult returns k*G, where G is the base point of the group and k is
+// a number in big-endian form.
+//
+// Multiples of G are mostly computed from secrets such as private keys and
+// nonces, so the constant-time algorithm is used here. It relies on a table
+// of precomputed multiples of G which is built on the first call.
+func (c *koblitzCurve) ScalarBaseMult(k []byte) (x, y *big.Int) {
+	var p jacobianPoint
+	var s scalarVal
+	s.setByteSlice(k)
+	return p.mulBase(&s).toAffine()
+}
+
+// scalarMultConstTime returns k*(x,y) in time that does not depend on the
+// value of k, which makes it suitable for secret scalars.
+func (c *koblitzCurve) scalarMultConstTime(x1, y1 *big.Int, k []byte) (x, y *big.Int) {
+	// (x1,y1) is the point at infinity
+	if x1 == nil {
+		return
+	}
+
+	var p jacobianPoint
+	var s scalarVal
+	p.setAffine(x1, y1)
+	s.setByteSlice(k)
+	return p.mulConstTime(&p, &s).toAffine()
+}
@@ -0,0 +1,160 @@
+package crypto_test
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+	kitcrypto "github.com/evercoinx/kit/crypto"
+)
+
+// affineSecp256k1 returns the generic affine implementation of the curve
+// which serves as a reference for the optimized one.
+func affineSecp256k1() interface {
+	Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int)
+	Double(x1, y1 *big.Int) (x, y *big.Int)
+	ScalarMult(x1, y1 *big.Int, k []byte) (x, y *big.Int)
+} {
+	params := crypto.Secp256k1().Params()
+	return kitcrypto.NewEllipticCurve(big.NewInt(0), params.B, params.P, params.N,
+		params.Gx, params.Gy, params.BitSize, params.Name)
+}
+
+func randomScalar(t testing.TB) []byte {
+	t.Helper()
+
+	k, err := rand.Int(rand.Reader, crypto.Secp256k1().Params().N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k.Bytes()
+}
+
+func TestKoblitzCurveMatchesAffine(t *testing.T) {
+	t.Parallel()
+
+	curve := crypto.Secp256k1()
+	ref := affineSecp256k1()
+	params := curve.Params()
+
+	for i := 0; i < 16; i++ {
+		x1, y1 := ref.ScalarMult(params.Gx, params.Gy, randomScalar(t))
+		x2, y2 := ref.ScalarMult(params.Gx, params.Gy, randomScalar(t))
+		k := randomScalar(t)
+
+		wantX, wantY := ref.Add(x1, y1, x2, y2)
+		if x, y := curve.Add(x1, y1, x2, y2); x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+			t.Fatalf("add: (%x,%x) != (%x,%x)", x, y, wantX, wantY)
+		}
+
+		wantX, wantY = ref.Double(x1, y1)
+		if x, y := curve.Double(x1, y1); x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+			t.Fatalf("double: (%x,%x) != (%x,%x)", x, y, wantX, wantY)
+		}
+
+		wantX, wantY = ref.ScalarMult(x1, y1, k)
+		if x, y := curve.ScalarMult(x1, y1, k); x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+			t.Fatalf("scalar mult: (%x,%x) != (%x,%x)", x, y, wantX, wantY)
+		}
+	}
+}
+
+func TestKoblitzCurveEdgeCases(t *testing.T) {
+	t.Parallel()
+
+	curve := crypto.Secp256k1()
+	params := curve.Params()
+	negGy := new(big.Int).Sub(params.P, params.Gy)
+
+	t.Run("G+(-G)", func(t *testing.T) {
+		if x, y := curve.Add(params.Gx, params.Gy, params.Gx, negGy); x != nil || y != nil {
+			t.Fatalf("(%x,%x) is not the point at infinity", x, y)
+		}
+	})
+
+	t.Run("G+G", func(t *testing.T) {
+		wantX, wantY := curve.Double(params.Gx, params.Gy)
+		if x, y := curve.Add(params.Gx, params.Gy, params.Gx, params.Gy); x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+			t.Fatalf("(%x,%x) != (%x,%x)", x, y, wantX, wantY)
+		}
+	})
+
+	t.Run("I+G", func(t *testing.T) {
+		if x, y := curve.Add(nil, nil, params.Gx, params.Gy); x.Cmp(params.Gx) != 0 || y.Cmp(params.Gy) != 0 {
+			t.Fatalf("(%x,%x) != G", x, y)
+		}
+	})
+
+	t.Run("k*I", func(t *testing.T) {
+		if x, y := curve.ScalarMult(nil, nil, []byte{7}); x != nil || y != nil {
+			t.Fatalf("(%x,%x) is not the point at infinity", x, y)
+		}
+	})
+
+	t.Run("(n-1)*G", func(t *testing.T) {
+		k := new(big.Int).Sub(params.N, big.NewInt(1))
+		if x, y := curve.ScalarBaseMult(k.Bytes()); x.Cmp(params.Gx) != 0 || y.Cmp(negGy) != 0 {
+			t.Fatalf("(%x,%x) != -G", x, y)
+		}
+	})
+
+	t.Run("out of range coordinates", func(t *testing.T) {
+		x := new(big.Int).Add(params.Gx, params.P)
+		if curve.IsOnCurve(x, params.Gy) {
+			t.Fatal("unreduced point is on the curve")
+		}
+	})
+}
+
+func BenchmarkScalarBaseMult(b *testing.B) {
+	curve := crypto.Secp256k1()
+	params := curve.Params()
+	k := randomScalar(b)
+
+	b.Run("fixed width", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			curve.ScalarBaseMult(k)
+		}
+	})
+
+	b.Run("big int affine", func(b *testing.B) {
+		b.ReportAllocs()
+		ref := affineSecp256k1()
+		for i := 0; i < b.N; i++ {
+			ref.ScalarMult(params.Gx, params.Gy, k)
+		}
+	})
+}
+
+func BenchmarkScalarMult(b *testing.B) {
+	curve := crypto.Secp256k1()
+	x, y := curve.ScalarBaseMult(randomScalar(b))
+	k := randomScalar(b)
+
+	b.Run("fixed width", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			curve.ScalarMult(x, y, k)
+		}
+	})
+
+	b.Run("big int affine", func(b *testing.B) {
+		b.ReportAllocs()
+		ref := affineSecp256k1()
+		for i := 0; i < b.N; i++ {
+			ref.ScalarMult(x, y, k)
+		}
+	})
+}
+
+func BenchmarkNewPrivateKey(b *testing.B) {
+	b.ReportAllocs()
+	k := randomScalar(b)
+	for i := 0; i < b.N; i++ {
+		if _, err := crypto.NewPrivateKeyFromInt(new(big.Int).SetBytes(k)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	derSequenceTag = 0x30
+	derIntegerTag  = 0x02
+
+	// minDERSignatureSize is the size of a signature with one-byte integers:
+	// 0x30 len 0x02 1 r 0x02 1 s
+	minDERSignatureSize = 8
+
+	// maxDERSignatureSize is the size of a signature with 33-byte integers:
+	// 0x30 len 0x02 33 r 0x02 33 s
+	maxDERSignatureSize = 72
+)
+
+// Serialize returns the signature in the strict DER form required by
+// BIP 66:
+//
+//	0x30 <total length> 0x02 <length of r> <r> 0x02 <length of s> <s>
+//
+// Both integers are encoded in the shortest big-endian form with a leading
+// zero byte if the high bit is set, so that they are never negative.
+func (sig *Signature) Serialize() []byte {
+	r := derInteger(sig.R)
+	s := derInteger(sig.S)
+
+	b := make([]byte, 0, 6+len(r)+len(s))
+	b = append(b, derSequenceTag, byte(4+len(r)+len(s)))
+	b = append(b, derIntegerTag, byte(len(r)))
+	b = append(b, r...)
+	b = append(b, derIntegerTag, byte(len(s)))
+	b = append(b, s...)
+	return b
+}
+
+func derInteger(n *big.Int) []byte {
+	b := n.Bytes()
+	if len(b) == 0 || b[0]&0x80 != 0 {
+		b = append([]byte{0x00}, b...)
+	}
+	return b
+}
+
+// ParseDERSignature parses a signature in the strict DER form and rejects
+// any encoding that violates the rules of BIP 66: negative integers,
+// excess zero padding, wrong length bytes or trailing data.
+//
+// Unlike the check in BIP 66 the input must not include a sighash type.
+func ParseDERSignature(b []byte) (*Signature, error) {
+	if len(b) < minDERSignatureSize || len(b) > maxDERSignatureSize {
+		return nil, fmt.Errorf("der: invalid signature size of %d bytes", len(b))
+	}
+	if b[0] != derSequenceTag {
+		return nil, errors.New("der: signature is not a sequence")
+	}
+	if int(b[1]) != len(b)-2 {
+		return nil, errors.New("der: invalid sequence length")
+	}
+
+	lenR := int(b[3])
+	if 5+lenR >= len(b) {
+		return nil, errors.New("der: r length overflows signature")
+	}
+	lenS := int(b[5+lenR])
+	if lenR+lenS+6 != len(b) {
+		return nil, errors.New("der: invalid s length")
+	}
+
+	r, err := parseStrictDERInteger(b[2:4+lenR], "r")
+	if err != nil {
+		return nil, err
+	}
+	s, err := parseStrictDERInteger(b[4+lenR:], "s")
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{R: r, S: s}, nil
+}
+
+// parseStrictDERInteger parses a tag-length-value encoded integer which is
+// known to have a consistent length byte.
+func parseStrictDERInteger(b []byte, name string) (*big.Int, error) {
+	if b[0] != derIntegerTag {
+		return nil, fmt.Errorf("der: %s is not an integer", name)
+	}
+
+	v := b[2:]
+	switch {
+	case len(v) == 0:
+		return nil, fmt.Errorf("der: %s is empty", name)
+	case v[0]&0x80 != 0:
+		return nil, fmt.Errorf("der: %s is negative", name)
+	case len(v) > 1 && v[0] == 0x00 && v[1]&0x80 == 0:
+		return nil, fmt.Errorf("der: %s has excess padding", name)
+	}
+	return new(big.Int).SetBytes(v), nil
+}
+
+// ParseLaxDERSignature parses a signature in a relaxed BER-like form in
+// the same way as the lax parser of libsecp256k1, which accepts signatures
+// found in the chain before BIP 66 was activated.
+//
+// Long-form lengths, excess padding, negative integers and trailing data are
+// tolerated. The integers must still fit 32 bytes once leading zeros are
+// stripped.
+func ParseLaxDERSignature(b []byte) (*Signature, error) {
+	pos := 0
+	if pos == len(b) || b[pos] != derSequenceTag {
+		return nil, errors.New("der: signature is not a sequence")
+	}
+	pos++
+
+	// the sequence length is ignored apart from skipping its bytes
+	if pos == len(b) {
+		return nil, errors.New("der: missing sequence length")
+	}
+	lenByte := int(b[pos])
+	pos++
+	if lenByte&0x80 != 0 {
+		lenByte -= 0x80
+		if lenByte > len(b)-pos {
+			return nil, errors.New("der: sequence length overflows signature")
+		}
+		pos += lenByte
+	}
+
+	r, pos, err := parseLaxDERInteger(b, pos, "r")
+	if err != nil {
+		return nil, err
+	}
+	s, _, err := parseLaxDERInteger(b, pos, "s")
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{R: r, S: s}, nil
+}
+
+// parseLaxDERInteger parses an integer starting at pos and returns the
+// position right after it.
+func parseLaxDERInteger(b []byte, pos int, name string) (*big.Int, int, error) {
+	if pos == len(b) || b[pos] != derIntegerTag {
+		return nil, 0, fmt.Errorf("der: %s is not an integer", name)
+	}
+	pos++
+
+	if pos == len(b) {
+		return nil, 0, fmt.Errorf("der: missing %s length", name)
+	}
+	length := int(b[pos])
+	pos++
+	if length&0x80 != 0 {
+		lenBytes := length - 0x80
+		if lenBytes > len(b)-pos {
+			return nil, 0, fmt.Errorf("der: %s length overflows signature", name)
+		}
+		for ; lenBytes > 0 && b[pos] == 0; lenBytes-- {
+			pos++
+		}
+		if lenBytes > 4 {
+			return nil, 0, fmt.Errorf("der: %s length is too large", name)
+		}
+
+		length = 0
+		for ; lenBytes > 0; lenBytes-- {
+			length = length<<8 + int(b[pos])
+			pos++
+		}
+	}
+	if length > len(b)-pos {
+		return nil, 0, fmt.Errorf("der: %s overflows signature", name)
+	}
+
+	v := b[pos : pos+length]
+	for len(v) > 0 && v[0] == 0 {
+		v = v[1:]
+	}
+	if len(v) > 32 {
+		return nil, 0, fmt.Errorf("der: %s overflows 32 bytes", name)
+	}
+	return new(big.Int).SetBytes(v), pos + length, nil
+}
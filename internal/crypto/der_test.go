@@ -0,0 +1,222 @@
+package crypto_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+)
+
+func TestSignatureSerialize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		r, s string
+		want string
+	}{
+		{
+			name: "padded r",
+			r:    "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8",
+			s:    "2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5",
+			want: "3045022100934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d802202442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5",
+		},
+		{
+			name: "unpadded r and s",
+			r:    "7063ae83e7f62bbb171798131b4a0564b956930092b33b07b395615d9ec7e15c",
+			s:    "58dfcc1e00a35e1572f366ffe34ba0fc47db1e7189759b9fb233c5b05ab388ea",
+			want: "304402207063ae83e7f62bbb171798131b4a0564b956930092b33b07b395615d9ec7e15c022058dfcc1e00a35e1572f366ffe34ba0fc47db1e7189759b9fb233c5b05ab388ea",
+		},
+		{
+			name: "short integers",
+			r:    "1",
+			s:    "80",
+			want: "300702010102020080",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := new(big.Int).SetString(tt.r, 16)
+			s, _ := new(big.Int).SetString(tt.s, 16)
+			sig := &crypto.Signature{R: r, S: s}
+
+			got := hex.EncodeToString(sig.Serialize())
+			if got != tt.want {
+				t.Fatalf("%s != %s", got, tt.want)
+			}
+
+			want, err := hex.DecodeString(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parsed, err := crypto.ParseDERSignature(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.R.Cmp(r) != 0 || parsed.S.Cmp(s) != 0 {
+				t.Fatalf("(%x,%x) != (%x,%x)", parsed.R, parsed.S, r, s)
+			}
+			if !bytes.Equal(parsed.Serialize(), want) {
+				t.Fatalf("%x != %x", parsed.Serialize(), want)
+			}
+		})
+	}
+}
+
+func TestParseDERSignature(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sig     string
+		wantLax bool
+	}{
+		{
+			name: "too short",
+			sig:  "30050201010201",
+		},
+		{
+			name: "not a sequence",
+			sig:  "3106020101020101",
+		},
+		{
+			name:    "wrong sequence length",
+			sig:     "3007020101020101",
+			wantLax: true,
+		},
+		{
+			name: "r length overflows",
+			sig:  "3006020501020101",
+		},
+		{
+			name: "wrong s length",
+			sig:  "3006020101020201",
+		},
+		{
+			name: "r is not an integer",
+			sig:  "3006030101020101",
+		},
+		{
+			name:    "empty r",
+			sig:     "3006020002020101",
+			wantLax: true,
+		},
+		{
+			name:    "negative r",
+			sig:     "3006020181020101",
+			wantLax: true,
+		},
+		{
+			name:    "padded r",
+			sig:     "300702020001020101",
+			wantLax: true,
+		},
+		{
+			name:    "negative s",
+			sig:     "3006020101020181",
+			wantLax: true,
+		},
+		{
+			name:    "padded s",
+			sig:     "300702010102020001",
+			wantLax: true,
+		},
+		{
+			name:    "long form sequence length",
+			sig:     "308106020101020101",
+			wantLax: true,
+		},
+		{
+			name:    "trailing data",
+			sig:     "300602010102010100",
+			wantLax: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := hex.DecodeString(tt.sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := crypto.ParseDERSignature(b); err == nil {
+				t.Fatal("expected strict parsing error")
+			}
+
+			_, err = crypto.ParseLaxDERSignature(b)
+			if (err == nil) != tt.wantLax {
+				t.Fatalf("lax parsing: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseLaxDERSignature(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sig     string
+		wantR   int64
+		wantS   int64
+		wantErr bool
+	}{
+		{
+			name:  "strict signature",
+			sig:   "3006020101020102",
+			wantR: 1,
+			wantS: 2,
+		},
+		{
+			name:  "long form integer length",
+			sig:   "300702810101020102",
+			wantR: 1,
+			wantS: 2,
+		},
+		{
+			name:  "long form integer length with zero bytes",
+			sig:   "3008028300000101020102",
+			wantR: 1,
+			wantS: 2,
+		},
+		{
+			name:    "integer overflows 32 bytes",
+			sig:     "302702210101010101010101010101010101010101010101010101010101010101010101020102",
+			wantErr: true,
+		},
+		{
+			name:    "missing s",
+			sig:     "3003020101",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := hex.DecodeString(tt.sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sig, err := crypto.ParseLaxDERSignature(b)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if sig.R.Int64() != tt.wantR || sig.S.Int64() != tt.wantS {
+				t.Fatalf("(%d,%d) != (%d,%d)", sig.R, sig.S, tt.wantR, tt.wantS)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"errors"
+)
+
+// ECDH returns the shared secret of the private key and the public key of a
+// peer. Like the default hash function of libsecp256k1, the secret is the
+// SHA-256 of the shared point d*Q in the 33-byte compressed form, so both
+// parties derive the same 32 bytes.
+//
+// The private key is secret, so the point is computed in constant time
+// rather than with the variable-time ScalarMult.
+func ECDH(key *PrivateKey, pub *PublicKey) ([]byte, error) {
+	if key == nil || !isValidScalar(key.D) {
+		return nil, ErrInvalidPrivateKey
+	}
+	if pub == nil || !pub.isValid() {
+		return nil, ErrInvalidPublicKey
+	}
+
+	x, y := secp256k1.scalarMultConstTime(pub.X, pub.Y, key.D.Bytes())
+	if x == nil {
+		// unreachable for a valid key and a point of the prime-order group
+		return nil, errors.New("crypto: shared point is at infinity")
+	}
+
+	shared := &PublicKey{X: x, Y: y}
+	sum := sha256.Sum256(shared.SerializeCompressed())
+	return sum[:], nil
+}
@@ -0,0 +1,114 @@
+package crypto_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+)
+
+func TestECDH(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		peer    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "d=1 and G",
+			key:  "1",
+			peer: "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			want: "0f715baf5d4c2ed329785cef29e562f73488c8a2bb9dbc5700b361d54b9b0554",
+		},
+		{
+			name: "small key",
+			key:  "5cd",
+			peer: "0337a4aef1f8423ca076e4b7d99a8cabff40ddb8231f2a9f01081f15d7fa65c1ba",
+			want: "d41ab8e54b90ae6d87ae2b9f5b928e1e65710a8345d3cc409edb45be6f07d1fb",
+		},
+		{
+			name: "large key",
+			key:  "f8b8af8ce3c7cca5e300d33939540c10d45ce001b8f252bfbc57ba0342904181",
+			peer: "03f028892bad7ed57d2fb57bf33081d5cfcf6f9ed3d3d7f159c2e2fff579dc341a",
+			want: "b210b9786cf8bbd9da80a3890d0cf5e1f7c4494da70fad8d49bad228f27c5d52",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, _ := new(big.Int).SetString(tt.key, 16)
+			key, err := crypto.NewPrivateKeyFromInt(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			peer, err := crypto.ParsePublicKey(mustDecodeHex(t, tt.peer))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := crypto.ECDH(key, peer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Fatalf("%x != %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestECDHSymmetric(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 8; i++ {
+		alice, err := crypto.GeneratePrivateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bob, err := crypto.GeneratePrivateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s1, err := crypto.ECDH(alice, bob.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		s2, err := crypto.ECDH(bob, alice.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(s1, s2) {
+			t.Fatalf("%x != %x", s1, s2)
+		}
+	}
+}
+
+func TestECDHInvalidKeys(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.NewPrivateKeyFromInt(big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offCurve := &crypto.PublicKey{X: big.NewInt(1), Y: big.NewInt(1)}
+	if _, err := crypto.ECDH(key, offCurve); !errors.Is(err, crypto.ErrInvalidPublicKey) {
+		t.Fatalf("%v != %v", err, crypto.ErrInvalidPublicKey)
+	}
+
+	zero := &crypto.PrivateKey{PublicKey: *key.Public(), D: big.NewInt(0)}
+	if _, err := crypto.ECDH(zero, key.Public()); !errors.Is(err, crypto.ErrInvalidPrivateKey) {
+		t.Fatalf("%v != %v", err, crypto.ErrInvalidPrivateKey)
+	}
+}
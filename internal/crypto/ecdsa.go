@@ -0,0 +1,124 @@
+package crypto
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Signature describes an ECDSA signature (r,s) over the secp256k1 curve.
+type Signature struct {
+	R, S *big.Int
+}
+
+// IsLowS reports whether s lies in the lower half of the group order as
+// required by BIP 62 and BIP 146.
+func (sig *Signature) IsLowS() bool {
+	return sig.S.Cmp(halfOrder) <= 0
+}
+
+// Sign signs a message hash with the private key.
+//
+// The nonce k is derived deterministically from the private key and the
+// hash according to RFC 6979, so signing the same hash twice yields the same
+// signature. The resulting s is normalized to the lower half of the group
+// order.
+func Sign(key *PrivateKey, hash []byte) (*Signature, error) {
+	sig, _, err := sign(key, hash)
+	return sig, err
+}
+
+// sign signs a message hash and also returns the recovery id of the
+// signature: bit 0 is the parity of the y of the nonce point R and bit 1 is
+// set if the x of R overflowed the group order.
+func sign(key *PrivateKey, hash []byte) (*Signature, byte, error) {
+	if key == nil || !isValidScalar(key.D) {
+		return nil, 0, ErrInvalidPrivateKey
+	}
+	if len(hash) == 0 {
+		return nil, 0, errors.New("crypto: message hash is empty")
+	}
+
+	n := secp256k1.Params().N
+	nonces := newNonceGenerator(key.D, hash, nil)
+
+	// the arithmetic on the private key and the nonce is performed on
+	// constant-time scalars
+	var d, e, k, kInv, r, s scalarVal
+	d.setBig(key.D)
+	e.setBig(hashToInt(hash))
+
+	for {
+		kn := nonces.next()
+		k.setBig(kn)
+
+		rx, ry := secp256k1.ScalarBaseMult(kn.Bytes())
+		recID := byte(ry.Bit(0))
+		if rx.Cmp(n) >= 0 {
+			recID |= 2
+		}
+
+		r.setBig(rx)
+		if r.isZero() == 1 {
+			continue
+		}
+
+		// s = k^-1(e+rd) % n
+		s.mul(&r, &d)
+		s.add(&s, &e)
+		s.mul(&s, kInv.inverse(&k))
+		if s.isZero() == 1 {
+			continue
+		}
+
+		// negating s corresponds to the nonce -k whose point has the
+		// opposite parity of y
+		sn := s.big()
+		if sn.Cmp(halfOrder) > 0 {
+			sn.Sub(n, sn)
+			recID ^= 1
+		}
+		return &Signature{R: r.big(), S: sn}, recID, nil
+	}
+}
+
+// Verify reports whether the signature of the message hash is valid for the
+// public key.
+//
+// Signatures with a high s are accepted as well; use IsLowS to enforce the
+// BIP 62 rule.
+func Verify(pub *PublicKey, hash []byte, sig *Signature) bool {
+	if pub == nil || sig == nil || !pub.isValid() {
+		return false
+	}
+	if !isValidScalar(sig.R) || !isValidScalar(sig.S) {
+		return false
+	}
+
+	n := secp256k1.Params().N
+	e := hashToInt(hash)
+	w := new(big.Int).ModInverse(sig.S, n)
+
+	// (x,y) = (ew % n)*G + (rw % n)*Q
+	u1 := new(big.Int).Mul(e, w)
+	u1.Mod(u1, n)
+	u2 := new(big.Int).Mul(sig.R, w)
+	u2.Mod(u2, n)
+
+	x1, y1 := secp256k1.ScalarBaseMult(u1.Bytes())
+	x2, y2 := secp256k1.ScalarMult(pub.X, pub.Y, u2.Bytes())
+	x, _ := secp256k1.Add(x1, y1, x2, y2)
+	if x == nil {
+		return false
+	}
+
+	x = new(big.Int).Mod(x, n)
+	return x.Cmp(sig.R) == 0
+}
+
+// hashToInt converts a message hash to an integer modulo n keeping only
+// its leftmost bits to match the bit length of n.
+func hashToInt(hash []byte) *big.Int {
+	n := secp256k1.Params().N
+	e := bitsToInt(hash, n.BitLen())
+	return e.Mod(e, n)
+}
@@ -0,0 +1,164 @@
+package crypto_test
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+)
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		key          string
+		message      string
+		wantR, wantS string
+	}{
+		{
+			name:    "d=1",
+			key:     "1",
+			message: "Satoshi Nakamoto",
+			wantR:   "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8",
+			wantS:   "2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5",
+		},
+		{
+			name:    "d=1 long message",
+			key:     "1",
+			message: "All those moments will be lost in time, like tears in rain. Time to die...",
+			wantR:   "8600dbd41e348fe5c9465ab92d23e3db8b98b873beecd930736488696438cb6b",
+			wantS:   "547fe64427496db33bf66019dacbf0039c04199abb0122918601db38a72cfc21",
+		},
+		{
+			name:    "d=n-1",
+			key:     "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+			message: "Satoshi Nakamoto",
+			wantR:   "fd567d121db66e382991534ada77a6bd3106f0a1098c231e47993447cd6af2d0",
+			wantS:   "6b39cd0eb1bc8603e159ef5c20a5c8ad685a45b06ce9bebed3f153d10d93bed5",
+		},
+		{
+			name:    "random key",
+			key:     "f8b8af8ce3c7cca5e300d33939540c10d45ce001b8f252bfbc57ba0342904181",
+			message: "Alan Turing",
+			wantR:   "7063ae83e7f62bbb171798131b4a0564b956930092b33b07b395615d9ec7e15c",
+			wantS:   "58dfcc1e00a35e1572f366ffe34ba0fc47db1e7189759b9fb233c5b05ab388ea",
+		},
+		{
+			name:    "normalized high s",
+			key:     "e91671c46231f833a6406ccbea0e3e392c76c167bac1cb013f6f1013980455c2",
+			message: "There is a computer disease that anybody who works with computers knows about. It's a very serious disease and it interferes completely with the work. The trouble with computers is that you 'play' with them!",
+			wantR:   "b552edd27580141f3b2a5463048cb7cd3e047b97c9f98076c32dbdf85a68718b",
+			wantS:   "279fa72dd19bfae05577e06c7c0c1900c371fcd5893f7e1d56a37d30174671f6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := new(big.Int).SetString(tt.key, 16)
+			key, err := crypto.NewPrivateKeyFromInt(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hash := sha256.Sum256([]byte(tt.message))
+			sig, err := crypto.Sign(key, hash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if sig.R.Text(16) != tt.wantR || sig.S.Text(16) != tt.wantS {
+				t.Fatalf("(%s,%s) != (%s,%s)", sig.R.Text(16), sig.S.Text(16), tt.wantR, tt.wantS)
+			}
+			if !sig.IsLowS() {
+				t.Fatal("signature has high s")
+			}
+			if !crypto.Verify(key.Public(), hash[:], sig) {
+				t.Fatal("signature is not verified")
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.NewPrivateKeyFromInt(big.NewInt(1485))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := sha256.Sum256([]byte("Hello, world!"))
+	sig, err := crypto.Sign(key, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := crypto.Secp256k1().Params().N
+	otherKey, err := crypto.NewPrivateKeyFromInt(big.NewInt(1486))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherHash := sha256.Sum256([]byte("Hello, world?"))
+
+	tests := []struct {
+		name string
+		pub  *crypto.PublicKey
+		hash []byte
+		sig  *crypto.Signature
+		want bool
+	}{
+		{
+			name: "valid signature",
+			pub:  key.Public(),
+			hash: hash[:],
+			sig:  sig,
+			want: true,
+		},
+		{
+			name: "high s",
+			pub:  key.Public(),
+			hash: hash[:],
+			sig:  &crypto.Signature{R: sig.R, S: new(big.Int).Sub(n, sig.S)},
+			want: true,
+		},
+		{
+			name: "another public key",
+			pub:  otherKey.Public(),
+			hash: hash[:],
+			sig:  sig,
+			want: false,
+		},
+		{
+			name: "another hash",
+			pub:  key.Public(),
+			hash: otherHash[:],
+			sig:  sig,
+			want: false,
+		},
+		{
+			name: "zero r",
+			pub:  key.Public(),
+			hash: hash[:],
+			sig:  &crypto.Signature{R: big.NewInt(0), S: sig.S},
+			want: false,
+		},
+		{
+			name: "s equals n",
+			pub:  key.Public(),
+			hash: hash[:],
+			sig:  &crypto.Signature{R: sig.R, S: n},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := crypto.Verify(tt.pub, tt.hash, tt.sig)
+			if got != tt.want {
+				t.Fatalf("%t != %t", got, tt.want)
+			}
+		})
+	}
+}
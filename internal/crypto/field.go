@@ -0,0 +1,246 @@
+package crypto
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// fieldVal describes an element of the secp256k1 field as four 64-bit limbs
+// in little-endian order. Values are always kept fully reduced modulo p.
+//
+// All operations run in constant time with respect to the values of the
+// operands, so field elements derived from secrets do not leak through
+// timing.
+type fieldVal [4]uint64
+
+// fieldP is the field order p = 2^256 - 2^32 - 977.
+var fieldP = fieldVal{0xfffffffefffffc2f, 0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff}
+
+// fieldReductionConst is c = 2^256 - p = 2^32 + 977. Thanks to the special
+// form of p the high half H of a 512-bit product H*2^256 + L reduces to
+// L + H*c.
+const fieldReductionConst = 0x1000003d1
+
+// fieldPMinus2 is the exponent p-2 used to compute inverses according to
+// Fermat's little theorem.
+var fieldPMinus2 = fieldVal{0xfffffffefffffc2d, 0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff}
+
+// fieldSqrtExponent is the exponent (p+1)/4 used to compute square roots,
+// which is possible since p % 4 = 3.
+var fieldSqrtExponent = fieldVal{0xffffffffbfffff0c, 0xffffffffffffffff, 0xffffffffffffffff, 0x3fffffffffffffff}
+
+// setInt sets f to a small integer.
+func (f *fieldVal) setInt(v uint64) *fieldVal {
+	*f = fieldVal{v}
+	return f
+}
+
+// setBytes sets f to the 32-byte big-endian value reduced modulo p.
+func (f *fieldVal) setBytes(b *[32]byte) *fieldVal {
+	for i := 0; i < 4; i++ {
+		j := 32 - 8*(i+1)
+		f[i] = uint64(b[j])<<56 | uint64(b[j+1])<<48 | uint64(b[j+2])<<40 | uint64(b[j+3])<<32 |
+			uint64(b[j+4])<<24 | uint64(b[j+5])<<16 | uint64(b[j+6])<<8 | uint64(b[j+7])
+	}
+	return f.reduce(0)
+}
+
+// setBig sets f to the integer v reduced modulo p.
+func (f *fieldVal) setBig(v *big.Int) *fieldVal {
+	var b [32]byte
+	if v.Sign() < 0 || v.BitLen() > 256 {
+		v = new(big.Int).Mod(v, secp256k1.params.P)
+	}
+	v.FillBytes(b[:])
+	return f.setBytes(&b)
+}
+
+// bytes returns f as 32 bytes in big-endian form.
+func (f *fieldVal) bytes() [32]byte {
+	var b [32]byte
+	for i := 0; i < 4; i++ {
+		j := 32 - 8*(i+1)
+		for k := 0; k < 8; k++ {
+			b[j+k] = byte(f[i] >> (56 - 8*k))
+		}
+	}
+	return b
+}
+
+// big returns f as an integer.
+func (f *fieldVal) big() *big.Int {
+	b := f.bytes()
+	return new(big.Int).SetBytes(b[:])
+}
+
+// isZero returns 1 if f is zero and 0 otherwise.
+func (f *fieldVal) isZero() uint64 {
+	v := f[0] | f[1] | f[2] | f[3]
+	// the top bit of v|-v is set for any non-zero v
+	return 1 ^ (v|-v)>>63
+}
+
+// equal returns 1 if f equals g and 0 otherwise.
+func (f *fieldVal) equal(g *fieldVal) uint64 {
+	d := fieldVal{f[0] ^ g[0], f[1] ^ g[1], f[2] ^ g[2], f[3] ^ g[3]}
+	return d.isZero()
+}
+
+// isOdd returns 1 if f is odd and 0 otherwise.
+func (f *fieldVal) isOdd() uint64 {
+	return f[0] & 1
+}
+
+// cmov sets f to g if flag is 1 and leaves it unchanged if flag is 0.
+func (f *fieldVal) cmov(g *fieldVal, flag uint64) *fieldVal {
+	mask := -flag
+	for i := range f {
+		f[i] ^= (f[i] ^ g[i]) & mask
+	}
+	return f
+}
+
+// reduce subtracts p from the 257-bit value carry*2^256 + f if it is not
+// less than p.
+func (f *fieldVal) reduce(carry uint64) *fieldVal {
+	var t fieldVal
+	var borrow uint64
+	t[0], borrow = bits.Sub64(f[0], fieldP[0], 0)
+	t[1], borrow = bits.Sub64(f[1], fieldP[1], borrow)
+	t[2], borrow = bits.Sub64(f[2], fieldP[2], borrow)
+	t[3], borrow = bits.Sub64(f[3], fieldP[3], borrow)
+
+	// keep the difference if the value overflowed 256 bits or p was
+	// subtracted without a borrow
+	return f.cmov(&t, carry|(borrow^1))
+}
+
+// add sets f = a + b.
+func (f *fieldVal) add(a, b *fieldVal) *fieldVal {
+	var carry uint64
+	f[0], carry = bits.Add64(a[0], b[0], 0)
+	f[1], carry = bits.Add64(a[1], b[1], carry)
+	f[2], carry = bits.Add64(a[2], b[2], carry)
+	f[3], carry = bits.Add64(a[3], b[3], carry)
+	return f.reduce(carry)
+}
+
+// sub sets f = a - b.
+func (f *fieldVal) sub(a, b *fieldVal) *fieldVal {
+	var borrow uint64
+	f[0], borrow = bits.Sub64(a[0], b[0], 0)
+	f[1], borrow = bits.Sub64(a[1], b[1], borrow)
+	f[2], borrow = bits.Sub64(a[2], b[2], borrow)
+	f[3], borrow = bits.Sub64(a[3], b[3], borrow)
+
+	// add p back if the difference is negative
+	mask := -borrow
+	var carry uint64
+	f[0], carry = bits.Add64(f[0], fieldP[0]&mask, 0)
+	f[1], carry = bits.Add64(f[1], fieldP[1]&mask, carry)
+	f[2], carry = bits.Add64(f[2], fieldP[2]&mask, carry)
+	f[3], _ = bits.Add64(f[3], fieldP[3]&mask, carry)
+	return f
+}
+
+// neg sets f = -a.
+func (f *fieldVal) neg(a *fieldVal) *fieldVal {
+	var zero fieldVal
+	return f.sub(&zero, a)
+}
+
+// double sets f = 2a.
+func (f *fieldVal) double(a *fieldVal) *fieldVal {
+	return f.add(a, a)
+}
+
+// mul sets f = a * b.
+func (f *fieldVal) mul(a, b *fieldVal) *fieldVal {
+	var t [8]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(a[i], b[j])
+			var c uint64
+			lo, c = bits.Add64(lo, t[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			t[i+j] = lo
+			carry = hi
+		}
+		t[i+4] = carry
+	}
+	return f.reduceWide(&t)
+}
+
+// sqr sets f = a^2.
+func (f *fieldVal) sqr(a *fieldVal) *fieldVal {
+	return f.mul(a, a)
+}
+
+// reduceWide sets f to the 512-bit value t reduced modulo p.
+func (f *fieldVal) reduceWide(t *[8]uint64) *fieldVal {
+	// r = L + H*c where t = H*2^256 + L, which fits 290 bits
+	var r [4]uint64
+	var carry uint64
+	for i := 0; i < 4; i++ {
+		hi, lo := bits.Mul64(t[4+i], fieldReductionConst)
+		var c uint64
+		lo, c = bits.Add64(lo, t[i], 0)
+		hi += c
+		lo, c = bits.Add64(lo, carry, 0)
+		hi += c
+		r[i] = lo
+		carry = hi
+	}
+
+	// fold the bits above 2^256 in the same way
+	hi, lo := bits.Mul64(carry, fieldReductionConst)
+	var c uint64
+	r[0], c = bits.Add64(r[0], lo, 0)
+	r[1], c = bits.Add64(r[1], hi, c)
+	r[2], c = bits.Add64(r[2], 0, c)
+	r[3], c = bits.Add64(r[3], 0, c)
+
+	// the value wrapped around 2^256 at most once and is small now, so
+	// adding c cannot overflow again
+	r[0], c = bits.Add64(r[0], c*fieldReductionConst, 0)
+	r[1], c = bits.Add64(r[1], 0, c)
+	r[2], c = bits.Add64(r[2], 0, c)
+	r[3], _ = bits.Add64(r[3], 0, c)
+
+	*f = r
+	return f.reduce(0)
+}
+
+// exp sets f = a^e. The running time depends on the exponent only, which is
+// always a public constant.
+func (f *fieldVal) exp(a, e *fieldVal) *fieldVal {
+	base := *a
+	var res fieldVal
+	res.setInt(1)
+	for i := 255; i >= 0; i-- {
+		res.sqr(&res)
+		if (e[i/64]>>(i%64))&1 == 1 {
+			res.mul(&res, &base)
+		}
+	}
+	*f = res
+	return f
+}
+
+// inverse sets f = a^-1 = a^(p-2). The inverse of zero is zero.
+func (f *fieldVal) inverse(a *fieldVal) *fieldVal {
+	return f.exp(a, &fieldPMinus2)
+}
+
+// sqrt sets f to a square root of a and reports whether a has one. If it
+// has not, f is left with a value whose square is -a.
+func (f *fieldVal) sqrt(a *fieldVal) bool {
+	var r, r2 fieldVal
+	r.exp(a, &fieldSqrtExponent)
+	r2.sqr(&r)
+	*f = r
+	return r2.equal(a) == 1
+}
@@ -0,0 +1,93 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func randomFieldInt(t testing.TB) *big.Int {
+	t.Helper()
+
+	v, err := rand.Int(rand.Reader, secp256k1.params.P)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestFieldVal(t *testing.T) {
+	t.Parallel()
+
+	p := secp256k1.params.P
+	pMinus1 := new(big.Int).Sub(p, big.NewInt(1))
+	edges := []*big.Int{big.NewInt(0), big.NewInt(1), pMinus1, new(big.Int).Rsh(p, 1)}
+
+	var ints []*big.Int
+	ints = append(ints, edges...)
+	for i := 0; i < 32; i++ {
+		ints = append(ints, randomFieldInt(t))
+	}
+
+	mod := func(v *big.Int) *big.Int { return v.Mod(v, p) }
+	for _, a := range ints {
+		for _, b := range edges {
+			var fa, fb, got fieldVal
+			fa.setBig(a)
+			fb.setBig(b)
+
+			if want := mod(new(big.Int).Add(a, b)); got.add(&fa, &fb).big().Cmp(want) != 0 {
+				t.Fatalf("%x+%x: %x != %x", a, b, got.big(), want)
+			}
+			if want := mod(new(big.Int).Sub(a, b)); got.sub(&fa, &fb).big().Cmp(want) != 0 {
+				t.Fatalf("%x-%x: %x != %x", a, b, got.big(), want)
+			}
+			if want := mod(new(big.Int).Mul(a, b)); got.mul(&fa, &fb).big().Cmp(want) != 0 {
+				t.Fatalf("%x*%x: %x != %x", a, b, got.big(), want)
+			}
+		}
+	}
+
+	for i := 1; i < len(ints); i++ {
+		a, b := ints[i-1], ints[i]
+		var fa, fb, got fieldVal
+		fa.setBig(a)
+		fb.setBig(b)
+
+		if want := mod(new(big.Int).Mul(a, b)); got.mul(&fa, &fb).big().Cmp(want) != 0 {
+			t.Fatalf("%x*%x: %x != %x", a, b, got.big(), want)
+		}
+		if want := mod(new(big.Int).Neg(a)); got.neg(&fa).big().Cmp(want) != 0 {
+			t.Fatalf("-%x: %x != %x", a, got.big(), want)
+		}
+		if a.Sign() != 0 {
+			want := new(big.Int).ModInverse(a, p)
+			if got.inverse(&fa).big().Cmp(want) != 0 {
+				t.Fatalf("%x^-1: %x != %x", a, got.big(), want)
+			}
+		}
+	}
+
+	t.Run("set unreduced bytes", func(t *testing.T) {
+		var b [32]byte
+		for i := range b {
+			b[i] = 0xff
+		}
+
+		var f fieldVal
+		want := new(big.Int).Sub(new(big.Int).SetBytes(b[:]), p)
+		if f.setBytes(&b).big().Cmp(want) != 0 {
+			t.Fatalf("%x != %x", f.big(), want)
+		}
+	})
+}
+
+func BenchmarkFieldMul(b *testing.B) {
+	var x, y fieldVal
+	x.setBig(randomFieldInt(b))
+	y.setBig(randomFieldInt(b))
+
+	for i := 0; i < b.N; i++ {
+		x.mul(&x, &y)
+	}
+}
@@ -0,0 +1,150 @@
+package crypto
+
+import "sync"
+
+const (
+	// genWindowBits is the width of the signed windows of a scalar used with
+	// the generator table.
+	genWindowBits = 5
+	genWindows    = (256 + genWindowBits - 1) / genWindowBits
+	// genWindowHalf is the largest absolute value of a signed window.
+	genWindowHalf = 1 << (genWindowBits - 1)
+)
+
+// generatorTable holds the affine multiples j*32^w*G for every 5-bit window
+// w of a scalar and every absolute window value j in 1..16. It takes 52 KiB
+// and lets mulBase compute k*G with 52 mixed additions of signed window
+// entries and no doublings. The additions dominate the running time, so
+// wider windows trade fewer additions for longer table scans and a larger
+// table. BenchmarkMulBase compares it with mulConstTime.
+type generatorTable [genWindows][genWindowHalf]affinePoint
+
+var (
+	genTable     *generatorTable
+	genTableOnce sync.Once
+)
+
+// getGeneratorTable returns the table of multiples of G building it on the
+// first call.
+func getGeneratorTable() *generatorTable {
+	genTableOnce.Do(func() {
+		var t generatorTable
+		var base jacobianPoint
+		base.setAffine(secp256k1.params.Gx, secp256k1.params.Gy)
+
+		var row [genWindowHalf]jacobianPoint
+		for w := range t {
+			// base = 32^w*G
+			row[0] = base
+			for j := 1; j < len(row); j++ {
+				row[j].add(&row[j-1], &base)
+			}
+			toAffineBatch(row[:], t[w][:])
+
+			for i := 0; i < genWindowBits; i++ {
+				base.double(&base)
+			}
+		}
+		genTable = &t
+	})
+	return genTable
+}
+
+// toAffineBatch converts the finite points to affine coordinates with a
+// single inversion using Montgomery's trick.
+func toAffineBatch(points []jacobianPoint, out []affinePoint) {
+	// prod[i] = Z0*Z1*...*Zi
+	prod := make([]fieldVal, len(points))
+	prod[0] = points[0].z
+	for i := 1; i < len(points); i++ {
+		prod[i].mul(&prod[i-1], &points[i].z)
+	}
+
+	var inv fieldVal
+	inv.inverse(&prod[len(prod)-1])
+	for i := len(points) - 1; i >= 0; i-- {
+		// zInv = 1/Zi, and inv becomes 1/(Z0*...*Zi-1)
+		zInv := inv
+		if i > 0 {
+			zInv.mul(&inv, &prod[i-1])
+			inv.mul(&inv, &points[i].z)
+		}
+
+		var zInv2, zInv3 fieldVal
+		zInv2.sqr(&zInv)
+		zInv3.mul(&zInv2, &zInv)
+		out[i].x.mul(&points[i].x, &zInv2)
+		out[i].y.mul(&points[i].y, &zInv3)
+	}
+}
+
+// mulBase sets p = k*G in constant time using the precomputed table unless
+// it is disabled by the secp256k1_nogentable build tag.
+func (p *jacobianPoint) mulBase(k *scalarVal) *jacobianPoint {
+	if !useGeneratorTable {
+		var g jacobianPoint
+		g.setAffine(secp256k1.params.Gx, secp256k1.params.Gy)
+		return p.mulConstTime(&g, k)
+	}
+	return p.mulBaseTable(k)
+}
+
+// mulBaseTable sets p = k*G in constant time as the sum of one table entry
+// per 5-bit window of k, so no doublings are needed at all. A window above
+// 16 is taken as the negative difference to 32 with a carry into the next
+// window, so every window selects one of 16 entries or none and the entry is
+// negated if needed.
+//
+// The running sum and the entry to be added are never equal or opposite
+// points. The sum P of the lower windows is less than 32^w in absolute value
+// while the entry is ±j*32^w with j >= 1, so P ∓ j*32^w is nonzero and less
+// than n in absolute value for all but the top window. The top window holds
+// bit 255 and a carry only, so P + j*2^255 = k is in 1..n-1, and
+// P - j*2^255 = k - j*2^256 is zero modulo n only if k is 2^256 - n,
+// 2^257 - 2n or 2^257 - n. The first two are below 2^130 and give a zero top
+// window, the last one exceeds n.
+func (p *jacobianPoint) mulBaseTable(k *scalarVal) *jacobianPoint {
+	t := getGeneratorTable()
+
+	var res jacobianPoint
+	res.setInfinity()
+	var carry uint64
+	for w := range t {
+		v := scalarWindow(k, w*genWindowBits, genWindowBits) + carry
+		carry = (genWindowHalf - v) >> 63
+		abs := v ^ ((v ^ (1<<genWindowBits - v)) & -carry)
+
+		var sel affinePoint
+		for j := range t[w] {
+			sel.cmov(&t[w][j], isEqualWord(uint64(j+1), abs))
+		}
+		var negY fieldVal
+		negY.neg(&sel.y)
+		sel.y.cmov(&negY, carry)
+
+		// the sum is meaningless if the running sum is at infinity, in
+		// which case it is the entry itself
+		var sum, selJ jacobianPoint
+		sum.addMixed(&res, &sel)
+		selJ.x, selJ.y = sel.x, sel.y
+		selJ.z.setInt(1)
+		sum.cmov(&selJ, res.z.isZero())
+
+		// a zero window selects no entry and leaves the running sum
+		res.cmov(&sum, 1^isEqualWord(abs, 0))
+	}
+
+	*p = res
+	return p
+}
+
+// scalarWindow returns the bits of k in the window of the given width
+// starting at the given bit, which may span two limbs.
+func scalarWindow(k *scalarVal, bit, width int) uint64 {
+	limb, shift := bit/64, bit%64
+	v := k[limb] >> shift
+	if shift+width > 64 && limb+1 < len(k) {
+		v |= k[limb+1] << (64 - shift)
+	}
+	return v & (1<<width - 1)
+}
@@ -0,0 +1,7 @@
+//go:build secp256k1_nogentable
+
+package crypto
+
+// useGeneratorTable disables the precomputed multiples of G, so ScalarBaseMult
+// falls back to the generic constant-time scalar multiplication.
+const useGeneratorTable = false
@@ -0,0 +1,7 @@
+//go:build !secp256k1_nogentable
+
+package crypto
+
+// useGeneratorTable enables the precomputed multiples of G in ScalarBaseMult.
+// Build with the secp256k1_nogentable tag to save the memory of the table.
+const useGeneratorTable = true
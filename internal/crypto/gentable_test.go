@@ -0,0 +1,67 @@
+package crypto
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestMulBaseTable(t *testing.T) {
+	t.Parallel()
+
+	n := secp256k1.params.N
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(16),
+		new(big.Int).Sub(n, big.NewInt(1)),
+		new(big.Int).Sub(n, big.NewInt(16)),
+		new(big.Int).Lsh(big.NewInt(15), 252),
+		big.NewInt(17),
+		big.NewInt(1<<genWindowBits - 1),
+		new(big.Int).Lsh(big.NewInt(1), 255),
+		new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), n),
+	}
+	for i := 0; i < 32; i++ {
+		scalars = append(scalars, randomScalarInt(t))
+	}
+
+	var g jacobianPoint
+	g.setAffine(secp256k1.params.Gx, secp256k1.params.Gy)
+
+	for _, k := range scalars {
+		var s scalarVal
+		s.setBig(k)
+
+		var got, want jacobianPoint
+		gotX, gotY := got.mulBaseTable(&s).toAffine()
+		wantX, wantY := want.mulConstTime(&g, &s).toAffine()
+		if !equalAffine(gotX, gotY, wantX, wantY) {
+			t.Fatalf("k=%x: (%x,%x) != (%x,%x)", k, gotX, gotY, wantX, wantY)
+		}
+	}
+}
+
+func BenchmarkMulBase(b *testing.B) {
+	var s scalarVal
+	s.setBig(randomScalarInt(b))
+
+	var g jacobianPoint
+	g.setAffine(secp256k1.params.Gx, secp256k1.params.Gy)
+
+	b.Run("table", func(b *testing.B) {
+		getGeneratorTable()
+		b.ResetTimer()
+
+		var p jacobianPoint
+		for i := 0; i < b.N; i++ {
+			p.mulBaseTable(&s)
+		}
+	})
+
+	b.Run("no table", func(b *testing.B) {
+		var p jacobianPoint
+		for i := 0; i < b.N; i++ {
+			p.mulConstTime(&g, &s)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package crypto
+
+import "math/big"
+
+// The curve has an efficiently computable endomorphism phi(x,y) = (beta*x,y)
+// where beta is a cube root of unity modulo p. It acts on every point as a
+// multiplication by lambda, a cube root of unity modulo n, so phi(P) =
+// lambda*P costs a single field multiplication.
+//
+// The GLV method splits a scalar k into k1 + k2*lambda where k1 and k2 are
+// only about 128 bits long, so k*P = k1*P + k2*phi(P) is computed with half
+// the number of doublings.
+var (
+	// endoBeta is beta in the field.
+	endoBeta = fieldVal{0xc1396c28719501ee, 0x9cf0497512f58995, 0x6e64479eac3434e9, 0x7ae96a2b657c0710}
+
+	// endoLambda is lambda modulo n.
+	endoLambda, _ = new(big.Int).SetString("5363ad4cc05c30e0a5261c028812645a122e22ea20816678df02967c1b23bd72", 16)
+
+	// The vectors (a1,b1) and (a2,b2) form a short basis of the lattice of
+	// pairs (x,y) with x + y*lambda = 0 modulo n.
+	endoA1, _ = new(big.Int).SetString("3086d221a7d46bcde86c90e49284eb15", 16)
+	endoB1, _ = new(big.Int).SetString("-e4437ed6010e88286f547fa90abfe4c3", 16)
+	endoA2, _ = new(big.Int).SetString("114ca50f7a8e2f3f657c1108d9d44cfd8", 16)
+	endoB2    = endoA1
+)
+
+// endomorphism sets p = phi(a) = lambda*a.
+func (p *jacobianPoint) endomorphism(a *jacobianPoint) *jacobianPoint {
+	// x = X/Z^2, so scaling X scales the affine x as well
+	p.x.mul(&a.x, &endoBeta)
+	p.y = a.y
+	p.z = a.z
+	return p
+}
+
+// splitScalar returns k1 and k2 such that k = k1 + k2*lambda modulo n and
+// both halves are at most 129 bits long in absolute value.
+//
+// The point (k,0) is rounded to the closest vector of the lattice spanned by
+// (a1,b1) and (a2,b2), and the difference is returned. The computation is
+// done in variable time.
+func splitScalar(k *big.Int) (k1, k2 *big.Int) {
+	n := secp256k1.params.N
+
+	// c1 = round(b2*k/n), c2 = round(-b1*k/n)
+	c1 := roundDiv(new(big.Int).Mul(endoB2, k), n)
+	c2 := roundDiv(new(big.Int).Mul(new(big.Int).Neg(endoB1), k), n)
+
+	// k1 = k - c1*a1 - c2*a2, k2 = -c1*b1 - c2*b2
+	k1 = new(big.Int).Sub(k, new(big.Int).Mul(c1, endoA1))
+	k1.Sub(k1, new(big.Int).Mul(c2, endoA2))
+	k2 = new(big.Int).Mul(c1, endoB1)
+	k2.Neg(k2)
+	k2.Sub(k2, new(big.Int).Mul(c2, endoB2))
+	return k1, k2
+}
+
+// roundDiv returns a/b rounded to the nearest integer for positive b.
+func roundDiv(a, b *big.Int) *big.Int {
+	q := new(big.Int).Lsh(a, 1)
+	q.Add(q, b)
+	return q.Div(q, new(big.Int).Lsh(b, 1))
+}
+
+// glvTerm holds a point multiplied by one half of a GLV-split scalar: the
+// multiples 0..15 of the point with the sign of the half applied and the
+// absolute value of the half.
+type glvTerm struct {
+	table  [1 << scalarWindowBits]jacobianPoint
+	k      scalarVal
+	bitLen int
+}
+
+// setGLVTerms splits k and fills the two terms for k1*a and k2*phi(a).
+func setGLVTerms(terms []glvTerm, a *jacobianPoint, k *scalarVal) {
+	k1, k2 := splitScalar(k.big())
+
+	t1, t2 := &terms[0], &terms[1]
+	t1.table[0].setInfinity()
+	t1.table[1] = *a
+	for i := 2; i < len(t1.table); i++ {
+		t1.table[i].add(&t1.table[i-1], a)
+	}
+	for i := range t2.table {
+		t2.table[i].endomorphism(&t1.table[i])
+		if k2.Sign() < 0 {
+			t2.table[i].neg(&t2.table[i])
+		}
+		if k1.Sign() < 0 {
+			t1.table[i].neg(&t1.table[i])
+		}
+	}
+
+	t1.k.setBig(new(big.Int).Abs(k1))
+	t1.bitLen = k1.BitLen()
+	t2.k.setBig(new(big.Int).Abs(k2))
+	t2.bitLen = k2.BitLen()
+}
+
+// sumGLVTerms sets p to the sum of all terms using Strauss' method: the
+// terms are processed in the same pass of 4-bit windows, so the doublings
+// are shared between them. The running time depends on the scalars.
+func (p *jacobianPoint) sumGLVTerms(terms []glvTerm) *jacobianPoint {
+	bitLen := 0
+	for i := range terms {
+		if terms[i].bitLen > bitLen {
+			bitLen = terms[i].bitLen
+		}
+	}
+
+	var res jacobianPoint
+	res.setInfinity()
+	for w := (bitLen+scalarWindowBits-1)/scalarWindowBits - 1; w >= 0; w-- {
+		for i := 0; i < scalarWindowBits; i++ {
+			res.double(&res)
+		}
+
+		bit := w * scalarWindowBits
+		for i := range terms {
+			t := &terms[i]
+			if idx := (t.k[bit/64] >> (bit % 64)) & (1<<scalarWindowBits - 1); idx != 0 {
+				res.add(&res, &t.table[idx])
+			}
+		}
+	}
+
+	*p = res
+	return p
+}
+
+// mulGLV sets p = k*a using the GLV method. The running time depends on k,
+// so it must only be used with public scalars.
+func (p *jacobianPoint) mulGLV(a *jacobianPoint, k *scalarVal) *jacobianPoint {
+	var terms [2]glvTerm
+	setGLVTerms(terms[:], a, k)
+	return p.sumGLVTerms(terms[:])
+}
+
+// mulMulti sets p = k[0]*a[0] + ... + k[m-1]*a[m-1]. Every product is split
+// with the GLV method and all of them share a single chain of about 128
+// doublings. The running time depends on the scalars, so it must only be
+// used with public scalars.
+func (p *jacobianPoint) mulMulti(a []jacobianPoint, k []scalarVal) *jacobianPoint {
+	terms := make([]glvTerm, 2*len(a))
+	for i := range a {
+		setGLVTerms(terms[2*i:], &a[i], &k[i])
+	}
+	return p.sumGLVTerms(terms)
+}
@@ -0,0 +1,92 @@
+package crypto
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSplitScalar(t *testing.T) {
+	t.Parallel()
+
+	n := secp256k1.params.N
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		new(big.Int).Sub(n, big.NewInt(1)),
+		new(big.Int).Set(endoLambda),
+	}
+	for i := 0; i < 64; i++ {
+		scalars = append(scalars, randomScalarInt(t))
+	}
+
+	for _, k := range scalars {
+		k1, k2 := splitScalar(k)
+		if k1.BitLen() > 129 || k2.BitLen() > 129 {
+			t.Fatalf("k=%x: halves %x and %x are too long", k, k1, k2)
+		}
+
+		got := new(big.Int).Mul(k2, endoLambda)
+		got.Add(got, k1)
+		got.Mod(got, n)
+		if got.Cmp(k) != 0 {
+			t.Fatalf("k=%x: k1 + k2*lambda = %x", k, got)
+		}
+	}
+}
+
+func TestMulGLV(t *testing.T) {
+	t.Parallel()
+
+	n := secp256k1.params.N
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(16),
+		new(big.Int).Sub(n, big.NewInt(1)),
+		new(big.Int).Set(endoLambda),
+		new(big.Int).Sub(n, endoLambda),
+	}
+	for i := 0; i < 32; i++ {
+		scalars = append(scalars, randomScalarInt(t))
+	}
+
+	var g, p jacobianPoint
+	g.setAffine(secp256k1.params.Gx, secp256k1.params.Gy)
+	var s scalarVal
+	p.mulVarTime(&g, s.setBig(randomScalarInt(t)))
+
+	for _, a := range []*jacobianPoint{&g, &p} {
+		for _, k := range scalars {
+			s.setBig(k)
+
+			var got, want jacobianPoint
+			gotX, gotY := got.mulGLV(a, &s).toAffine()
+			wantX, wantY := want.mulVarTime(a, &s).toAffine()
+			if !equalAffine(gotX, gotY, wantX, wantY) {
+				t.Fatalf("k=%x: (%x,%x) != (%x,%x)", k, gotX, gotY, wantX, wantY)
+			}
+		}
+	}
+}
+
+func BenchmarkMulGLV(b *testing.B) {
+	var s scalarVal
+	s.setBig(randomScalarInt(b))
+
+	var g jacobianPoint
+	g.setAffine(secp256k1.params.Gx, secp256k1.params.Gy)
+
+	b.Run("glv", func(b *testing.B) {
+		var p jacobianPoint
+		for i := 0; i < b.N; i++ {
+			p.mulGLV(&g, &s)
+		}
+	})
+
+	b.Run("binary", func(b *testing.B) {
+		var p jacobianPoint
+		for i := 0; i < b.N; i++ {
+			p.mulVarTime(&g, &s)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+const (
+	// PrivateKeySize is the size of a serialized private key in bytes.
+	PrivateKeySize = 32
+
+	// PublicKeyCompressedSize is the size of a public key serialized in
+	// the SEC compressed form: a 1-byte prefix followed by x.
+	PublicKeyCompressedSize = 33
+
+	// PublicKeyUncompressedSize is the size of a public key serialized in
+	// the SEC uncompressed form: a 1-byte prefix followed by x and y.
+	PublicKeyUncompressedSize = 65
+)
+
+const (
+	pubKeyPrefixEven         = 0x02
+	pubKeyPrefixOdd          = 0x03
+	pubKeyPrefixUncompressed = 0x04
+)
+
+var (
+	ErrInvalidPrivateKey = errors.New("crypto: private key is out of range [1, n-1]")
+	ErrInvalidPublicKey  = errors.New("crypto: public key is not on the curve")
+)
+
+// PublicKey describes a point (x,y) on the secp256k1 curve.
+type PublicKey struct {
+	X, Y *big.Int
+}
+
+// PrivateKey describes a secret scalar d in the range [1, n-1] together with
+// the public key d*G derived from it.
+type PrivateKey struct {
+	PublicKey
+	D *big.Int
+}
+
+// NewPrivateKey creates a private key from a 32-byte scalar in big-endian form.
+func NewPrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != PrivateKeySize {
+		return nil, fmt.Errorf("crypto: private key must be %d bytes, got %d", PrivateKeySize, len(b))
+	}
+	return NewPrivateKeyFromInt(new(big.Int).SetBytes(b))
+}
+
+// NewPrivateKeyFromInt creates a private key from a scalar d and derives its
+// public key as d*G.
+func NewPrivateKeyFromInt(d *big.Int) (*PrivateKey, error) {
+	if !isValidScalar(d) {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	d = new(big.Int).Set(d)
+	x, y := secp256k1.ScalarBaseMult(d.Bytes())
+	return &PrivateKey{
+		PublicKey: PublicKey{X: x, Y: y},
+		D:         d,
+	}, nil
+}
+
+// GeneratePrivateKey creates a private key from random bytes read from rand,
+// which is usually crypto/rand.Reader.
+//
+// Candidates outside the range [1, n-1] are rejected and drawn again, so the
+// resulting scalar is distributed uniformly.
+func GeneratePrivateKey(rand io.Reader) (*PrivateKey, error) {
+	b := make([]byte, PrivateKeySize)
+	for {
+		if _, err := io.ReadFull(rand, b); err != nil {
+			return nil, fmt.Errorf("crypto: unable to read random bytes: %w", err)
+		}
+
+		d := new(big.Int).SetBytes(b)
+		if isValidScalar(d) {
+			return NewPrivateKeyFromInt(d)
+		}
+	}
+}
+
+// Bytes returns the private key as a 32-byte scalar in big-endian form.
+func (k *PrivateKey) Bytes() []byte {
+	return k.D.FillBytes(make([]byte, PrivateKeySize))
+}
+
+// Public returns the public key corresponding to the private key.
+func (k *PrivateKey) Public() *PublicKey {
+	return &k.PublicKey
+}
+
+// IsEqual reports whether both public keys describe the same point.
+func (k *PublicKey) IsEqual(other *PublicKey) bool {
+	return k.X.Cmp(other.X) == 0 && k.Y.Cmp(other.Y) == 0
+}
+
+// SerializeCompressed returns the public key in the 33-byte SEC compressed
+// form where the prefix 0x02 or 0x03 encodes the parity of y.
+func (k *PublicKey) SerializeCompressed() []byte {
+	b := make([]byte, PublicKeyCompressedSize)
+	b[0] = pubKeyPrefixEven
+	if k.Y.Bit(0) == 1 {
+		b[0] = pubKeyPrefixOdd
+	}
+	k.X.FillBytes(b[1:])
+	return b
+}
+
+// SerializeUncompressed returns the public key in the 65-byte SEC
+// uncompressed form with the prefix 0x04.
+func (k *PublicKey) SerializeUncompressed() []byte {
+	b := make([]byte, PublicKeyUncompressedSize)
+	b[0] = pubKeyPrefixUncompressed
+	k.X.FillBytes(b[1:33])
+	k.Y.FillBytes(b[33:])
+	return b
+}
+
+// ParsePublicKey parses a public key in either the SEC compressed or
+// uncompressed form and checks that the resulting point lies on the curve.
+func ParsePublicKey(b []byte) (*PublicKey, error) {
+	if len(b) == 0 {
+		return nil, errors.New("crypto: public key is empty")
+	}
+
+	params := secp256k1.Params()
+	switch {
+	case len(b) == PublicKeyCompressedSize && (b[0] == pubKeyPrefixEven || b[0] == pubKeyPrefixOdd):
+		x := new(big.Int).SetBytes(b[1:])
+		if x.Cmp(params.P) >= 0 {
+			return nil, ErrInvalidPublicKey
+		}
+
+		y, err := decompressY(x, b[0] == pubKeyPrefixOdd)
+		if err != nil {
+			return nil, err
+		}
+		return &PublicKey{X: x, Y: y}, nil
+
+	case len(b) == PublicKeyUncompressedSize && b[0] == pubKeyPrefixUncompressed:
+		pub := &PublicKey{
+			X: new(big.Int).SetBytes(b[1:33]),
+			Y: new(big.Int).SetBytes(b[33:]),
+		}
+		if !pub.isValid() {
+			return nil, ErrInvalidPublicKey
+		}
+		return pub, nil
+
+	default:
+		return nil, fmt.Errorf("crypto: invalid public key of %d bytes with prefix 0x%02x", len(b), b[0])
+	}
+}
+
+// isValid reports whether the public key is a finite point on the curve with
+// both coordinates reduced modulo p.
+func (k *PublicKey) isValid() bool {
+	p := secp256k1.Params().P
+	if k.X == nil || k.Y == nil || k.X.Sign() < 0 || k.Y.Sign() < 0 {
+		return false
+	}
+	if k.X.Cmp(p) >= 0 || k.Y.Cmp(p) >= 0 {
+		return false
+	}
+	return secp256k1.IsOnCurve(k.X, k.Y)
+}
+
+// decompressY recovers y from x by solving the curve equation
+// y^2 = x^3 + 7 for the root with the requested parity.
+func decompressY(x *big.Int, odd bool) (*big.Int, error) {
+	var fx, c, y, b fieldVal
+	fx.setBig(x)
+	c.sqr(&fx)
+	c.mul(&c, &fx)
+	c.add(&c, b.setInt(7))
+
+	if !y.sqrt(&c) {
+		return nil, ErrInvalidPublicKey
+	}
+	if (y.isOdd() == 1) != odd {
+		y.neg(&y)
+	}
+	return y.big(), nil
+}
+
+// isValidScalar reports whether d lies in the range [1, n-1].
+func isValidScalar(d *big.Int) bool {
+	return d != nil && d.Sign() == 1 && d.Cmp(secp256k1.Params().N) < 0
+}
@@ -0,0 +1,252 @@
+package crypto_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+)
+
+func TestNewPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		key          string
+		wantX, wantY string
+		wantErr      bool
+	}{
+		{
+			name:  "d=1",
+			key:   "0000000000000000000000000000000000000000000000000000000000000001",
+			wantX: "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			wantY: "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+		},
+		{
+			name:  "d=7",
+			key:   "0000000000000000000000000000000000000000000000000000000000000007",
+			wantX: "5cbdf0646e5db4eaa398f365f2ea7a0e3d419b7e0330e39ce92bddedcac4f9bc",
+			wantY: "6aebca40ba255960a3178d6d861a54dba813d0b813fde7b5a5082628087264da",
+		},
+		{
+			name:  "d=n-1",
+			key:   "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+			wantX: "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			wantY: "b7c52588d95c3b9aa25b0403f1eef75702e84bb7597aabe663b82f6f04ef2777",
+		},
+		{
+			name:    "d=0",
+			key:     "0000000000000000000000000000000000000000000000000000000000000000",
+			wantErr: true,
+		},
+		{
+			name:    "d=n",
+			key:     "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+			wantErr: true,
+		},
+		{
+			name:    "short key",
+			key:     "01",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := hex.DecodeString(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := crypto.NewPrivateKey(b)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.X.Text(16) != tt.wantX || got.Y.Text(16) != tt.wantY {
+				t.Fatalf("(%s,%s) != (%s,%s)", got.X.Text(16), got.Y.Text(16), tt.wantX, tt.wantY)
+			}
+			if !bytes.Equal(got.Bytes(), b) {
+				t.Fatalf("%x != %x", got.Bytes(), b)
+			}
+		})
+	}
+}
+
+func TestNewPrivateKeyFromInt(t *testing.T) {
+	t.Parallel()
+
+	if _, err := crypto.NewPrivateKeyFromInt(big.NewInt(-1)); !errors.Is(err, crypto.ErrInvalidPrivateKey) {
+		t.Fatalf("%v != %v", err, crypto.ErrInvalidPrivateKey)
+	}
+
+	d := big.NewInt(1485)
+	key, err := crypto.NewPrivateKeyFromInt(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.SetInt64(0)
+	if key.D.Int64() != 1485 {
+		t.Fatalf("private key shares the scalar with the caller: %d", key.D)
+	}
+	if key.X.Text(16) != "c982196a7466fbbbb0e27a940b6af926c1a74d5ad07128c82824a11b5398afda" {
+		t.Fatalf("unexpected public key x: %s", key.X.Text(16))
+	}
+}
+
+func TestGeneratePrivateKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("random source", func(t *testing.T) {
+		key, err := crypto.GeneratePrivateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !crypto.Secp256k1().IsOnCurve(key.X, key.Y) {
+			t.Fatal("public key is not on the curve")
+		}
+	})
+
+	t.Run("out of range candidates", func(t *testing.T) {
+		src := bytes.NewReader(append(
+			bytes.Repeat([]byte{0xff}, 2*crypto.PrivateKeySize),
+			append(make([]byte, crypto.PrivateKeySize-1), 0x07)...,
+		))
+
+		key, err := crypto.GeneratePrivateKey(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.D.Int64() != 7 {
+			t.Fatalf("%d != 7", key.D)
+		}
+	})
+
+	t.Run("exhausted source", func(t *testing.T) {
+		if _, err := crypto.GeneratePrivateKey(bytes.NewReader(nil)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestPublicKeySerialization(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		d            int64
+		compressed   string
+		uncompressed string
+	}{
+		{
+			name:         "G",
+			d:            1,
+			compressed:   "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			uncompressed: "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+		},
+		{
+			name:         "7*G",
+			d:            7,
+			compressed:   "025cbdf0646e5db4eaa398f365f2ea7a0e3d419b7e0330e39ce92bddedcac4f9bc",
+			uncompressed: "045cbdf0646e5db4eaa398f365f2ea7a0e3d419b7e0330e39ce92bddedcac4f9bc6aebca40ba255960a3178d6d861a54dba813d0b813fde7b5a5082628087264da",
+		},
+		{
+			name:         "1485*G",
+			d:            1485,
+			compressed:   "03c982196a7466fbbbb0e27a940b6af926c1a74d5ad07128c82824a11b5398afda",
+			uncompressed: "04c982196a7466fbbbb0e27a940b6af926c1a74d5ad07128c82824a11b5398afda7a91f9eae64438afb9ce6448a1c133db2d8fb9254e4546b6f001637d50901f55",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := crypto.NewPrivateKeyFromInt(big.NewInt(tt.d))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, enc := range []struct {
+				got  []byte
+				want string
+			}{
+				{key.SerializeCompressed(), tt.compressed},
+				{key.SerializeUncompressed(), tt.uncompressed},
+			} {
+				if hex.EncodeToString(enc.got) != enc.want {
+					t.Fatalf("%x != %s", enc.got, enc.want)
+				}
+
+				b, err := hex.DecodeString(enc.want)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				pub, err := crypto.ParsePublicKey(b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !pub.IsEqual(key.Public()) {
+					t.Fatalf("(%x,%x) != (%x,%x)", pub.X, pub.Y, key.X, key.Y)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{
+			name: "empty key",
+			key:  "",
+		},
+		{
+			name: "invalid prefix",
+			key:  "0579be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		},
+		{
+			name: "compressed prefix with uncompressed size",
+			key:  "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+		},
+		{
+			name: "x without square root",
+			key:  "020000000000000000000000000000000000000000000000000000000000000005",
+		},
+		{
+			name: "x equals p",
+			key:  "02fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f",
+		},
+		{
+			name: "point not on curve",
+			key:  "045cbdf0646e5db4eaa398f365f2ea7a0e3d419b7e0330e39ce92bddedcac4f9bc6aebca40ba255960a3178d6d861a54dba813d0b813fde7b5a5082628087264db",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := hex.DecodeString(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := crypto.ParsePublicKey(b); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,481 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/evercoinx/bitcoin/internal/hash"
+)
+
+const (
+	// MuSigPubNonceSize is the size of a public nonce: two points in the
+	// compressed form.
+	MuSigPubNonceSize = 2 * PublicKeyCompressedSize
+
+	// MuSigSecNonceSize is the size of a secret nonce: two scalars followed
+	// by the compressed public key of the signer.
+	MuSigSecNonceSize = 2*32 + PublicKeyCompressedSize
+
+	// MuSigAggNonceSize is the size of an aggregate nonce.
+	MuSigAggNonceSize = MuSigPubNonceSize
+
+	// MuSigPartialSigSize is the size of a partial signature.
+	MuSigPartialSigSize = 32
+)
+
+const (
+	tagMuSigKeyAggList  = "KeyAgg list"
+	tagMuSigKeyAggCoeff = "KeyAgg coefficient"
+	tagMuSigAux         = "MuSig/aux"
+	tagMuSigNonce       = "MuSig/nonce"
+	tagMuSigNonceCoeff  = "MuSig/noncecoef"
+)
+
+// MuSigKeyAggContext describes the aggregate public key of a set of signers
+// according to BIP 327 together with the tweaks applied to it.
+type MuSigKeyAggContext struct {
+	pubKeys [][]byte
+	q       *PublicKey
+	// gacc accumulates the negations of Q and tacc the sum of the tweaks
+	gacc, tacc scalarVal
+}
+
+// MuSigKeySort returns the public keys sorted by their compressed form, which
+// makes the aggregate key independent of the order of the signers.
+func MuSigKeySort(pubs []*PublicKey) []*PublicKey {
+	sorted := append([]*PublicKey(nil), pubs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].SerializeCompressed(), sorted[j].SerializeCompressed()) < 0
+	})
+	return sorted
+}
+
+// MuSigKeyAgg aggregates the public keys of the signers into a single key
+// Q = a_1*P_1 + ... + a_u*P_u. The coefficients a_i commit to the whole list
+// of keys, which prevents rogue key attacks. The order of the keys matters.
+func MuSigKeyAgg(pubs []*PublicKey) (*MuSigKeyAggContext, error) {
+	if len(pubs) == 0 {
+		return nil, errors.New("musig: no public keys to aggregate")
+	}
+
+	pubKeys := make([][]byte, len(pubs))
+	points := make([]jacobianPoint, len(pubs))
+	for i, pub := range pubs {
+		if pub == nil || !pub.isValid() {
+			return nil, fmt.Errorf("musig: invalid public key of signer %d", i)
+		}
+		pubKeys[i] = pub.SerializeCompressed()
+		points[i].setAffine(pub.X, pub.Y)
+	}
+
+	listHash := musigHashKeys(pubKeys)
+	second := musigSecondKey(pubKeys)
+	coeffs := make([]scalarVal, len(pubs))
+	for i, pk := range pubKeys {
+		coeffs[i] = musigKeyAggCoeff(listHash, pk, second)
+	}
+
+	var q jacobianPoint
+	x, y := q.mulMulti(points, coeffs).toAffine()
+	if x == nil {
+		return nil, errors.New("musig: aggregate public key is the point at infinity")
+	}
+
+	ctx := &MuSigKeyAggContext{
+		pubKeys: pubKeys,
+		q:       &PublicKey{X: x, Y: y},
+	}
+	ctx.gacc.setInt(1)
+	return ctx, nil
+}
+
+// PublicKey returns the aggregate public key with all tweaks applied. Its
+// x-only form is the key that verifies the final signature.
+func (c *MuSigKeyAggContext) PublicKey() *PublicKey {
+	return &PublicKey{X: new(big.Int).Set(c.q.X), Y: new(big.Int).Set(c.q.Y)}
+}
+
+// ApplyTweak returns a new context with the 32-byte tweak added to the
+// aggregate key. A plain tweak is used for BIP 32 derivation, an x-only tweak
+// for Taproot commitments where Q is first negated if its y is odd.
+func (c *MuSigKeyAggContext) ApplyTweak(tweak []byte, xOnly bool) (*MuSigKeyAggContext, error) {
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+
+	var g scalarVal
+	g.setInt(1)
+	qy := c.q.Y
+	if xOnly && c.q.Y.Bit(0) == 1 {
+		g.neg(&g)
+		qy = new(big.Int).Sub(secp256k1.params.P, c.q.Y)
+	}
+
+	// Q' = g*Q + t*G
+	tb := t.bytes()
+	tx, ty := secp256k1.ScalarBaseMult(tb[:])
+	x, y := secp256k1.Add(c.q.X, qy, tx, ty)
+	if x == nil {
+		return nil, ErrTweakInfinity
+	}
+
+	next := &MuSigKeyAggContext{
+		pubKeys: c.pubKeys,
+		q:       &PublicKey{X: x, Y: y},
+	}
+	next.gacc.mul(&g, &c.gacc)
+	next.tacc.mul(&g, &c.tacc)
+	next.tacc.add(&next.tacc, t)
+	return next, nil
+}
+
+// MuSigNonceGen generates a secret nonce and the corresponding public nonce
+// of the signer with the public key pub. The 32 random bytes read from rand
+// are the only required source of entropy; the optional private key, x-only
+// aggregate key, message and extra input are mixed in as a defense in depth.
+// A nil message means that no message is known yet, while an empty one is
+// the empty message.
+//
+// The secret nonce must be used for a single signing session only. The Sign
+// method of the session wipes it to prevent accidental reuse.
+func MuSigNonceGen(rand io.Reader, key *PrivateKey, pub *PublicKey, aggPubKey, msg, extra []byte) (secNonce, pubNonce []byte, err error) {
+	if pub == nil || !pub.isValid() {
+		return nil, nil, ErrInvalidPublicKey
+	}
+	if key != nil && !isValidScalar(key.D) {
+		return nil, nil, ErrInvalidPrivateKey
+	}
+
+	r := make([]byte, 32)
+	if _, err := io.ReadFull(rand, r); err != nil {
+		return nil, nil, fmt.Errorf("musig: unable to read random bytes: %w", err)
+	}
+	if key != nil {
+		d := key.Bytes()
+		for i, b := range hash.TaggedHash(tagMuSigAux, r) {
+			r[i] = d[i] ^ b
+		}
+	}
+
+	msgPrefixed := []byte{0}
+	if msg != nil {
+		var size [8]byte
+		binary.BigEndian.PutUint64(size[:], uint64(len(msg)))
+		msgPrefixed = append(append([]byte{1}, size[:]...), msg...)
+	}
+	var extraSize [4]byte
+	binary.BigEndian.PutUint32(extraSize[:], uint32(len(extra)))
+
+	pk := pub.SerializeCompressed()
+	secNonce = make([]byte, 0, MuSigSecNonceSize)
+	pubNonce = make([]byte, 0, MuSigPubNonceSize)
+	for i := 0; i < 2; i++ {
+		// k_i = hash_nonce(rand || len(pk) || pk || len(aggpk) || aggpk ||
+		// m_prefixed || len(in) || in || i) % n
+		var k scalarVal
+		k.setByteSlice(hash.TaggedHash(tagMuSigNonce,
+			r,
+			[]byte{byte(len(pk))}, pk,
+			[]byte{byte(len(aggPubKey))}, aggPubKey,
+			msgPrefixed,
+			extraSize[:], extra,
+			[]byte{byte(i)},
+		))
+		if k.isZero() == 1 {
+			return nil, nil, errors.New("musig: nonce is zero")
+		}
+
+		kb := k.bytes()
+		secNonce = append(secNonce, kb[:]...)
+		rx, ry := secp256k1.ScalarBaseMult(kb[:])
+		pubNonce = append(pubNonce, (&PublicKey{X: rx, Y: ry}).SerializeCompressed()...)
+	}
+	secNonce = append(secNonce, pk...)
+	return secNonce, pubNonce, nil
+}
+
+// MuSigNonceAgg sums the public nonces of all signers into the aggregate
+// nonce. A sum at infinity is encoded as 33 zero bytes.
+func MuSigNonceAgg(pubNonces [][]byte) ([]byte, error) {
+	aggNonce := make([]byte, 0, MuSigAggNonceSize)
+	for j := 0; j < 2; j++ {
+		var x, y *big.Int
+		for i, pn := range pubNonces {
+			if len(pn) != MuSigPubNonceSize {
+				return nil, fmt.Errorf("musig: public nonce of signer %d must be %d bytes, got %d", i, MuSigPubNonceSize, len(pn))
+			}
+			r, err := ParsePublicKey(pn[j*PublicKeyCompressedSize : (j+1)*PublicKeyCompressedSize])
+			if err != nil {
+				return nil, fmt.Errorf("musig: invalid public nonce of signer %d: %w", i, err)
+			}
+			x, y = secp256k1.Add(x, y, r.X, r.Y)
+		}
+		aggNonce = append(aggNonce, serializeCompressedExt(x, y)...)
+	}
+	return aggNonce, nil
+}
+
+// MuSigSession describes the values shared by all signers that sign a message
+// with an aggregate nonce and an aggregate key.
+type MuSigSession struct {
+	keyAgg *MuSigKeyAggContext
+	msg    []byte
+	// b is the nonce coefficient, r the final nonce point and e the
+	// BIP 340 challenge
+	b, e scalarVal
+	r    *PublicKey
+}
+
+// NewMuSigSession starts a signing session of the message with the aggregate
+// nonce and the aggregate key including its tweaks.
+func NewMuSigSession(aggNonce []byte, keyAgg *MuSigKeyAggContext, msg []byte) (*MuSigSession, error) {
+	if len(aggNonce) != MuSigAggNonceSize {
+		return nil, fmt.Errorf("musig: aggregate nonce must be %d bytes, got %d", MuSigAggNonceSize, len(aggNonce))
+	}
+
+	r1x, r1y, err := parseCompressedExt(aggNonce[:PublicKeyCompressedSize])
+	if err != nil {
+		return nil, fmt.Errorf("musig: invalid aggregate nonce: %w", err)
+	}
+	r2x, r2y, err := parseCompressedExt(aggNonce[PublicKeyCompressedSize:])
+	if err != nil {
+		return nil, fmt.Errorf("musig: invalid aggregate nonce: %w", err)
+	}
+
+	s := &MuSigSession{keyAgg: keyAgg, msg: msg}
+	s.b.setByteSlice(hash.TaggedHash(tagMuSigNonceCoeff, aggNonce, keyAgg.q.SerializeXOnly(), msg))
+
+	// R = R1 + b*R2 replaced with G at infinity
+	bb := s.b.bytes()
+	x, y := secp256k1.ScalarMult(r2x, r2y, bb[:])
+	x, y = secp256k1.Add(r1x, r1y, x, y)
+	if x == nil {
+		x, y = secp256k1.params.Gx, secp256k1.params.Gy
+	}
+	s.r = &PublicKey{X: x, Y: y}
+	s.e.setBig(schnorrChallenge(x, keyAgg.q.X, msg))
+	return s, nil
+}
+
+// Sign creates the partial signature of the signer with the private key
+// using its secret nonce. The secret nonce is wiped before it is used, so a
+// second call with it fails.
+func (s *MuSigSession) Sign(secNonce []byte, key *PrivateKey) ([]byte, error) {
+	if len(secNonce) != MuSigSecNonceSize {
+		return nil, fmt.Errorf("musig: secret nonce must be %d bytes, got %d", MuSigSecNonceSize, len(secNonce))
+	}
+	if key == nil || !isValidScalar(key.D) {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	var kb1, kb2 [32]byte
+	copy(kb1[:], secNonce[:32])
+	copy(kb2[:], secNonce[32:64])
+	pk := append([]byte(nil), secNonce[64:]...)
+	for i := range secNonce {
+		secNonce[i] = 0
+	}
+
+	var k1, k2 scalarVal
+	_, overflow1 := k1.setBytes(&kb1)
+	_, overflow2 := k2.setBytes(&kb2)
+	if overflow1 || overflow2 || k1.isZero() == 1 || k2.isZero() == 1 {
+		return nil, errors.New("musig: secret nonce is out of range or has been used")
+	}
+	if !bytes.Equal(pk, key.SerializeCompressed()) {
+		return nil, errors.New("musig: secret nonce belongs to another public key")
+	}
+
+	a, err := s.keyAggCoeff(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	// the public nonce is needed to check the result
+	x1, y1 := secp256k1.ScalarBaseMult(kb1[:])
+	x2, y2 := secp256k1.ScalarBaseMult(kb2[:])
+	pubNonce := append((&PublicKey{X: x1, Y: y1}).SerializeCompressed(), (&PublicKey{X: x2, Y: y2}).SerializeCompressed()...)
+
+	if s.r.Y.Bit(0) == 1 {
+		k1.neg(&k1)
+		k2.neg(&k2)
+	}
+
+	// d = g*gacc*d'
+	var d scalarVal
+	d.setBig(key.D)
+	d.mul(&d, &s.keyAgg.gacc)
+	if s.keyAgg.q.Y.Bit(0) == 1 {
+		d.neg(&d)
+	}
+
+	// s = k1 + b*k2 + e*a*d
+	var sig, t scalarVal
+	t.mul(&s.b, &k2)
+	sig.add(&k1, &t)
+	t.mul(&s.e, &a)
+	t.mul(&t, &d)
+	sig.add(&sig, &t)
+
+	psig := sig.bytes()
+	if !s.VerifyPartial(psig[:], pubNonce, key.Public()) {
+		return nil, errors.New("musig: produced partial signature is invalid")
+	}
+	return psig[:], nil
+}
+
+// VerifyPartial reports whether the partial signature is valid for the
+// public nonce and the public key of a signer in the session.
+func (s *MuSigSession) VerifyPartial(psig, pubNonce []byte, pub *PublicKey) bool {
+	if len(psig) != MuSigPartialSigSize || len(pubNonce) != MuSigPubNonceSize || pub == nil || !pub.isValid() {
+		return false
+	}
+
+	var buf [32]byte
+	copy(buf[:], psig)
+	var sig scalarVal
+	if _, overflow := sig.setBytes(&buf); overflow {
+		return false
+	}
+
+	r1, err := ParsePublicKey(pubNonce[:PublicKeyCompressedSize])
+	if err != nil {
+		return false
+	}
+	r2, err := ParsePublicKey(pubNonce[PublicKeyCompressedSize:])
+	if err != nil {
+		return false
+	}
+
+	a, err := s.keyAggCoeff(pub.SerializeCompressed())
+	if err != nil {
+		return false
+	}
+
+	// Re = R1 + b*R2, negated if R has an odd y
+	bb := s.b.bytes()
+	rx, ry := secp256k1.ScalarMult(r2.X, r2.Y, bb[:])
+	rx, ry = secp256k1.Add(r1.X, r1.Y, rx, ry)
+	if rx != nil && s.r.Y.Bit(0) == 1 {
+		ry = new(big.Int).Sub(secp256k1.params.P, ry)
+	}
+
+	// s*G = Re + e*a*g*gacc*P
+	var c scalarVal
+	c.mul(&s.e, &a)
+	c.mul(&c, &s.keyAgg.gacc)
+	if s.keyAgg.q.Y.Bit(0) == 1 {
+		c.neg(&c)
+	}
+	cb := c.bytes()
+	px, py := secp256k1.ScalarMult(pub.X, pub.Y, cb[:])
+	wantX, wantY := secp256k1.Add(rx, ry, px, py)
+
+	sb := sig.bytes()
+	x, y := secp256k1.ScalarBaseMult(sb[:])
+	if x == nil || wantX == nil {
+		return x == nil && wantX == nil
+	}
+	return x.Cmp(wantX) == 0 && y.Cmp(wantY) == 0
+}
+
+// Aggregate combines the partial signatures of all signers into a BIP 340
+// signature valid for the x-only aggregate key.
+func (s *MuSigSession) Aggregate(psigs [][]byte) (*SchnorrSignature, error) {
+	var sum scalarVal
+	for i, psig := range psigs {
+		if len(psig) != MuSigPartialSigSize {
+			return nil, fmt.Errorf("musig: partial signature of signer %d must be %d bytes, got %d", i, MuSigPartialSigSize, len(psig))
+		}
+
+		var buf [32]byte
+		copy(buf[:], psig)
+		var si scalarVal
+		if _, overflow := si.setBytes(&buf); overflow {
+			return nil, fmt.Errorf("musig: partial signature of signer %d is out of range", i)
+		}
+		sum.add(&sum, &si)
+	}
+
+	// s = s_1 + ... + s_u + e*g*tacc
+	var t scalarVal
+	t.mul(&s.e, &s.keyAgg.tacc)
+	if s.keyAgg.q.Y.Bit(0) == 1 {
+		t.neg(&t)
+	}
+	sum.add(&sum, &t)
+
+	return &SchnorrSignature{R: new(big.Int).Set(s.r.X), S: sum.big()}, nil
+}
+
+// keyAggCoeff returns the key aggregation coefficient of a signer in the
+// session.
+func (s *MuSigSession) keyAggCoeff(pk []byte) (scalarVal, error) {
+	found := false
+	for _, k := range s.keyAgg.pubKeys {
+		if bytes.Equal(k, pk) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return scalarVal{}, errors.New("musig: public key is not a signer of the session")
+	}
+	return musigKeyAggCoeff(musigHashKeys(s.keyAgg.pubKeys), pk, musigSecondKey(s.keyAgg.pubKeys)), nil
+}
+
+// musigHashKeys returns the hash of the list of compressed public keys.
+func musigHashKeys(pubKeys [][]byte) []byte {
+	return hash.TaggedHash(tagMuSigKeyAggList, pubKeys...)
+}
+
+// musigSecondKey returns the first key which differs from the first one in
+// the list, or 33 zero bytes if all keys are equal.
+func musigSecondKey(pubKeys [][]byte) []byte {
+	for _, pk := range pubKeys[1:] {
+		if !bytes.Equal(pk, pubKeys[0]) {
+			return pk
+		}
+	}
+	return make([]byte, PublicKeyCompressedSize)
+}
+
+// musigKeyAggCoeff returns the coefficient of the key pk. The second distinct
+// key always gets 1, which saves a multiplication in the aggregation.
+func musigKeyAggCoeff(listHash, pk, second []byte) scalarVal {
+	var a scalarVal
+	if bytes.Equal(pk, second) {
+		a.setInt(1)
+		return a
+	}
+	a.setByteSlice(hash.TaggedHash(tagMuSigKeyAggCoeff, listHash, pk))
+	return a
+}
+
+// serializeCompressedExt returns the compressed form of the point (x,y) or
+// 33 zero bytes for the point at infinity.
+func serializeCompressedExt(x, y *big.Int) []byte {
+	if x == nil {
+		return make([]byte, PublicKeyCompressedSize)
+	}
+	return (&PublicKey{X: x, Y: y}).SerializeCompressed()
+}
+
+// parseCompressedExt parses a point in the compressed form where 33 zero
+// bytes stand for the point at infinity.
+func parseCompressedExt(b []byte) (x, y *big.Int, err error) {
+	if bytes.Equal(b, make([]byte, PublicKeyCompressedSize)) {
+		return nil, nil, nil
+	}
+	pub, err := ParsePublicKey(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pub.X, pub.Y, nil
+}
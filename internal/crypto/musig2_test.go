@@ -0,0 +1,748 @@
+package crypto_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+)
+
+// The vectors below are taken from the reference implementation of BIP 327.
+
+var musigKeyAggPubKeys = []string{
+	"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+	"03DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+	"023590A94E768F8E1815C2F24B4D80A8E3149316C3518CE7B7AD338368D038CA66",
+	"020000000000000000000000000000000000000000000000000000000000000005",
+	"02FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30",
+	"04F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+	"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9",
+}
+
+const (
+	musigSignKey      = "7FB9E0E687ADA1EEBF7ECFE2F21E73EBDB51A7D450948DFE8D76D7F2D1007671"
+	musigSignSecNonce = "508B81A611F100A6B2B6B29656590898AF488BCF2E1F55CF22E5CFB84421FE61" +
+		"FA27FD49B1D50085B481285E1CA205D55C82CC1B31FF5CD54A489829355901F7" +
+		"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9"
+	musigSignAggNonce = "028465FCF0BBDBCF443AABCCE533D42B4B5A10966AC09A49655E8C42DAAB8FCD61" +
+		"037496A3CC86926D452CAFCFD55D25972CA1675D549310DE296BFF42F72EEEA8C9"
+	musigSignMsg = "F95466D086770E689964664219266FE5ED215C92AE20BAB5C9D79ADDDDF3C0CF"
+)
+
+var musigSignPubNonces = []string{
+	"0337C87821AFD50A8644D820A8F3E02E499C931865C2360FB43D0A0D20DAFE07EA" +
+		"0287BF891D2A6DEAEBADC909352AA9405D1428C15F4B75F04DAE642A95C2548480",
+	"0279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798" +
+		"0279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798",
+	"032DE2662628C90B03F5E720284EB52FF7D71F4284F627B68A853D78C78E1FFE93" +
+		"03E4C5524E83FFE1493B9077CF1CA6BEB2090C93D930321071AD40B2F44E599046",
+	"0237C87821AFD50A8644D820A8F3E02E499C931865C2360FB43D0A0D20DAFE07EA" +
+		"0387BF891D2A6DEAEBADC909352AA9405D1428C15F4B75F04DAE642A95C2548480",
+}
+
+func parsePublicKeys(t *testing.T, keys []string, indices []int) []*crypto.PublicKey {
+	t.Helper()
+
+	pubs := make([]*crypto.PublicKey, len(indices))
+	for i, idx := range indices {
+		pub, err := crypto.ParsePublicKey(mustDecodeHex(t, keys[idx]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubs[i] = pub
+	}
+	return pubs
+}
+
+func TestMuSigKeyAgg(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		keys []int
+		want string
+	}{
+		{
+			name: "three keys",
+			keys: []int{0, 1, 2},
+			want: "90539EEDE565F5D054F32CC0C220126889ED1E5D193BAF15AEF344FE59D4610C",
+		},
+		{
+			name: "three keys reversed",
+			keys: []int{2, 1, 0},
+			want: "6204DE8B083426DC6EAF9502D27024D53FC826BF7D2012148A0575435DF54B2B",
+		},
+		{
+			name: "same key",
+			keys: []int{0, 0, 0},
+			want: "B436E3BAD62B8CD409969A224731C193D051162D8C5AE8B109306127DA3AA935",
+		},
+		{
+			name: "repeated keys",
+			keys: []int{0, 0, 1, 1},
+			want: "69BC22BFA5D106306E48A20679DE1D7389386124D07571D0D872686028C26A3E",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, err := crypto.MuSigKeyAgg(parsePublicKeys(t, musigKeyAggPubKeys, tt.keys))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(ctx.PublicKey().SerializeXOnly()); !strings.EqualFold(got, tt.want) {
+				t.Fatalf("%s != %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMuSigKeyAggInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, idx := range []int{3, 4, 5} {
+		if _, err := crypto.ParsePublicKey(mustDecodeHex(t, musigKeyAggPubKeys[idx])); err == nil {
+			t.Fatalf("public key %d: expected error", idx)
+		}
+	}
+
+	if _, err := crypto.MuSigKeyAgg(nil); err == nil {
+		t.Fatal("expected error for no keys")
+	}
+
+	ctx, err := crypto.MuSigKeyAgg(parsePublicKeys(t, musigKeyAggPubKeys, []int{0, 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := mustDecodeHex(t, "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141")
+	if _, err := ctx.ApplyTweak(order, true); !errors.Is(err, crypto.ErrInvalidTweak) {
+		t.Fatalf("%v != %v", err, crypto.ErrInvalidTweak)
+	}
+
+	ctx, err = crypto.MuSigKeyAgg(parsePublicKeys(t, musigKeyAggPubKeys, []int{6}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweak := mustDecodeHex(t, "252E4BD67410A76CDF933D30EAA1608214037F1B105A013ECCD3C5C184A6110B")
+	if _, err := ctx.ApplyTweak(tweak, false); !errors.Is(err, crypto.ErrTweakInfinity) {
+		t.Fatalf("%v != %v", err, crypto.ErrTweakInfinity)
+	}
+}
+
+func TestMuSigNonceGen(t *testing.T) {
+	t.Parallel()
+
+	const pubKey = "024D4B6CD1361032CA9BD2AEB9D900AA4D45D9EAD80AC9423374C451A7254D0766"
+	tests := []struct {
+		name         string
+		key          string
+		pubKey       string
+		aggPubKey    string
+		msg          []byte
+		extra        string
+		wantSecNonce string
+		wantPubNonce string
+	}{
+		{
+			name:         "all inputs",
+			key:          strings.Repeat("02", 32),
+			pubKey:       pubKey,
+			aggPubKey:    strings.Repeat("07", 32),
+			msg:          bytes.Repeat([]byte{0x01}, 32),
+			extra:        strings.Repeat("08", 32),
+			wantSecNonce: "B114E502BEAA4E301DD08A50264172C84E41650E6CB726B410C0694D59EFFB6495B5CAF28D045B973D63E3C99A44B807BDE375FD6CB39E46DC4A511708D0E9D2" + pubKey,
+			wantPubNonce: "02F7BE7089E8376EB355272368766B17E88E7DB72047D05E56AA881EA52B3B35DF02C29C8046FDD0DED4C7E55869137200FBDBFE2EB654267B6D7013602CAED3115A",
+		},
+		{
+			name:         "empty message",
+			key:          strings.Repeat("02", 32),
+			pubKey:       pubKey,
+			aggPubKey:    strings.Repeat("07", 32),
+			msg:          []byte{},
+			extra:        strings.Repeat("08", 32),
+			wantSecNonce: "E862B068500320088138468D47E0E6F147E01B6024244AE45EAC40ACE5929B9F0789E051170B9E705D0B9EB49049A323BBBBB206D8E05C19F46C6228742AA7A9" + pubKey,
+			wantPubNonce: "023034FA5E2679F01EE66E12225882A7A48CC66719B1B9D3B6C4DBD743EFEDA2C503F3FD6F01EB3A8E9CB315D73F1F3D287CAFBB44AB321153C6287F407600205109",
+		},
+		{
+			name:         "long message",
+			key:          strings.Repeat("02", 32),
+			pubKey:       pubKey,
+			aggPubKey:    strings.Repeat("07", 32),
+			msg:          bytes.Repeat([]byte{0x02}, 38),
+			extra:        strings.Repeat("08", 32),
+			wantSecNonce: "AB8CB70A1634576612A129302DEB0783B99A4D94AF4824E4474025C37C7DE414FCD2289E3F429FB5F4D1ACA0F2601C36E9AC9F30EDC0AD9C549AA5F7E19B4547" + pubKey,
+			wantPubNonce: "03E5EC8FE8ED253E944A64B9B3492BDF7CE4F7A2F0672BDE221E5A05A2141D213602C5665BA9D03201F74D8662A033902AE02C90EFA75D66FC0ADE0F60218FF0042A",
+		},
+		{
+			name:         "public key only",
+			pubKey:       "02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+			wantSecNonce: "89BDD787D0284E5E4D5FC572E49E316BAB7E21E3B1830DE37DFE80156FA41A6D0B17AE8D024C53679699A6FD7944D9C4A366B514BAF43088E0708B1023DD2897" + "02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+			wantPubNonce: "02C96E7CB1E8AA5DAC64D872947914198F607D90ECDE5200DE52978AD5DED63C000299EC5117C2D29EDEE8A2092587C3909BE694D5CFF0667D6C02EA4059F7CD9786",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var key *crypto.PrivateKey
+			if tt.key != "" {
+				var err error
+				if key, err = crypto.NewPrivateKey(mustDecodeHex(t, tt.key)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			pub, err := crypto.ParsePublicKey(mustDecodeHex(t, tt.pubKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rand := bytes.NewReader(bytes.Repeat([]byte{0x0f}, 32))
+			secNonce, pubNonce, err := crypto.MuSigNonceGen(rand, key, pub, mustDecodeHex(t, tt.aggPubKey), tt.msg, mustDecodeHex(t, tt.extra))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(secNonce); !strings.EqualFold(got, tt.wantSecNonce) {
+				t.Fatalf("secret nonce: %s != %s", got, tt.wantSecNonce)
+			}
+			if got := hex.EncodeToString(pubNonce); !strings.EqualFold(got, tt.wantPubNonce) {
+				t.Fatalf("public nonce: %s != %s", got, tt.wantPubNonce)
+			}
+		})
+	}
+}
+
+func TestMuSigNonceAgg(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		pubNonces []string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "two nonces",
+			pubNonces: []string{
+				"020151C80F435648DF67A22B749CD798CE54E0321D034B92B709B567D60A42E66603BA47FBC1834437B3212E89A84D8425E7BF12E0245D98262268EBDCB385D50641",
+				"03FF406FFD8ADB9CD29877E4985014F66A59F6CD01C0E88CAA8E5F3166B1F676A60248C264CDD57D3C24D79990B0F865674EB62A0F9018277A95011B41BFC193B833",
+			},
+			want: "035FE1873B4F2967F52FEA4A06AD5A8ECCBE9D0FD73068012C894E2E87CCB5804B024725377345BDE0E9C33AF3C43C0A29A9249F2F2956FA8CFEB55C8573D0262DC8",
+		},
+		{
+			name:      "sum at infinity",
+			pubNonces: []string{musigSignPubNonces[0], musigSignPubNonces[3]},
+			want:      strings.Repeat("00", crypto.MuSigAggNonceSize),
+		},
+		{
+			name: "invalid point",
+			pubNonces: []string{
+				musigSignPubNonces[0],
+				"020000000000000000000000000000000000000000000000000000000000000009" + musigSignPubNonces[0][66:],
+			},
+			wantErr: true,
+		},
+		{
+			name:      "short nonce",
+			pubNonces: []string{musigSignPubNonces[0][:64]},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pubNonces := make([][]byte, len(tt.pubNonces))
+			for i, pn := range tt.pubNonces {
+				pubNonces[i] = mustDecodeHex(t, pn)
+			}
+
+			got, err := crypto.MuSigNonceAgg(pubNonces)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != strings.ToLower(tt.want) {
+				t.Fatalf("%x != %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMuSigSign(t *testing.T) {
+	t.Parallel()
+
+	signPubKeys := []string{
+		"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9",
+		"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"02DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA661",
+	}
+
+	tests := []struct {
+		name     string
+		keys     []int
+		nonces   []int
+		aggNonce string
+		msg      string
+		signer   int
+		want     string
+	}{
+		{
+			name:   "signer first",
+			keys:   []int{0, 1, 2},
+			nonces: []int{0, 1, 2},
+			msg:    musigSignMsg,
+			signer: 0,
+			want:   "012ABBCB52B3016AC03AD82395A1A415C48B93DEF78718E62A7A90052FE224FB",
+		},
+		{
+			name:   "signer second",
+			keys:   []int{1, 0, 2},
+			nonces: []int{1, 0, 2},
+			msg:    musigSignMsg,
+			signer: 1,
+			want:   "9FF2F7AAA856150CC8819254218D3ADEEB0535269051897724F9DB3789513A52",
+		},
+		{
+			name:   "signer last",
+			keys:   []int{1, 2, 0},
+			nonces: []int{1, 2, 0},
+			msg:    musigSignMsg,
+			signer: 2,
+			want:   "FA23C359F6FAC4E7796BB93BC9F0532A95468C539BA20FF86D7C76ED92227900",
+		},
+		{
+			name:     "aggregate nonce at infinity",
+			keys:     []int{0, 1},
+			nonces:   []int{0, 3},
+			aggNonce: strings.Repeat("00", crypto.MuSigAggNonceSize),
+			msg:      musigSignMsg,
+			signer:   0,
+			want:     "AE386064B26105404798F75DE2EB9AF5EDA5387B064B83D049CB7C5E08879531",
+		},
+		{
+			name:   "empty message",
+			keys:   []int{0, 1, 2},
+			nonces: []int{0, 1, 2},
+			signer: 0,
+			want:   "D7D63FFD644CCDA4E62BC2BC0B1D02DD32A1DC3030E155195810231D1037D82D",
+		},
+		{
+			name:   "long message",
+			keys:   []int{0, 1, 2},
+			nonces: []int{0, 1, 2},
+			msg:    strings.Repeat("26", 38),
+			signer: 0,
+			want:   "E184351828DA5094A97C79CABDAAA0BFB87608C32E8829A4DF5340A6F243B78C",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := crypto.NewPrivateKey(mustDecodeHex(t, musigSignKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+			keyAgg, err := crypto.MuSigKeyAgg(parsePublicKeys(t, signPubKeys, tt.keys))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pubNonces := make([][]byte, len(tt.nonces))
+			for i, idx := range tt.nonces {
+				pubNonces[i] = mustDecodeHex(t, musigSignPubNonces[idx])
+			}
+			aggNonce, err := crypto.MuSigNonceAgg(pubNonces)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantAggNonce := tt.aggNonce
+			if wantAggNonce == "" {
+				wantAggNonce = musigSignAggNonce
+			}
+			if hex.EncodeToString(aggNonce) != strings.ToLower(wantAggNonce) {
+				t.Fatalf("aggregate nonce: %x != %s", aggNonce, wantAggNonce)
+			}
+
+			session, err := crypto.NewMuSigSession(aggNonce, keyAgg, mustDecodeHex(t, tt.msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			secNonce := mustDecodeHex(t, musigSignSecNonce)
+			psig, err := session.Sign(secNonce, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(psig); !strings.EqualFold(got, tt.want) {
+				t.Fatalf("%s != %s", got, tt.want)
+			}
+			if !session.VerifyPartial(psig, pubNonces[tt.signer], key.Public()) {
+				t.Fatal("partial signature is invalid")
+			}
+
+			// the secret nonce is wiped after use
+			if _, err := session.Sign(secNonce, key); err == nil {
+				t.Fatal("expected error for reused secret nonce")
+			}
+		})
+	}
+}
+
+func TestMuSigSignInvalid(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.NewPrivateKey(mustDecodeHex(t, musigSignKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := mustDecodeHex(t, musigSignMsg)
+
+	// the signer is not part of the aggregate key
+	keyAgg, err := crypto.MuSigKeyAgg(parsePublicKeys(t, musigKeyAggPubKeys, []int{0, 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := crypto.NewMuSigSession(mustDecodeHex(t, musigSignAggNonce), keyAgg, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.Sign(mustDecodeHex(t, musigSignSecNonce), key); err == nil {
+		t.Fatal("expected error for a key outside the session")
+	}
+
+	// the second half of the aggregate nonce is not on the curve
+	invalid := musigSignAggNonce[:66] + "020000000000000000000000000000000000000000000000000000000000000009"
+	if _, err := crypto.NewMuSigSession(mustDecodeHex(t, invalid), keyAgg, msg); err == nil {
+		t.Fatal("expected error for an invalid aggregate nonce")
+	}
+
+	// the secret nonce belongs to another signer
+	other, err := crypto.NewPrivateKeyFromInt(big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.Sign(mustDecodeHex(t, musigSignSecNonce), other); err == nil {
+		t.Fatal("expected error for a secret nonce of another key")
+	}
+}
+
+func TestMuSigTweak(t *testing.T) {
+	t.Parallel()
+
+	tweakPubKeys := []string{
+		"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9",
+		"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"02DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+	}
+	tweaks := []string{
+		"E8F791FF9225A2AF0102AFFF4A9A723D9612A682A25EBE79802B263CDFCD83BB",
+		"AE2EA797CC0FE72AC5B97B97F3C6957D7E4199A167A58EB08BCAFFDA70AC0455",
+		"F52ECBC565B3D8BEA2DFD5B75A4F457E54369809322E4120831626F290FA87E0",
+		"1969AD73CC177FA0B4FCED6DF1F7BF9907E665FDE9BA196A74FED0A3CF5AEF9D",
+	}
+
+	tests := []struct {
+		name   string
+		tweaks []int
+		xOnly  []bool
+		want   string
+	}{
+		{
+			name:   "x-only tweak",
+			tweaks: []int{0},
+			xOnly:  []bool{true},
+			want:   "E28A5C66E61E178C2BA19DB77B6CF9F7E2F0F56C17918CD13135E60CC848FE91",
+		},
+		{
+			name:   "plain tweak",
+			tweaks: []int{0},
+			xOnly:  []bool{false},
+			want:   "38B0767798252F21BF5702C48028B095428320F73A4B14DB1E25DE58543D2D2D",
+		},
+		{
+			name:   "plain and x-only tweaks",
+			tweaks: []int{0, 1},
+			xOnly:  []bool{false, true},
+			want:   "408A0A21C4A0F5DACAF9646AD6EB6FECD7F7A11F03ED1F48DFFF2185BC2C2408",
+		},
+		{
+			name:   "four tweaks",
+			tweaks: []int{0, 1, 2, 3},
+			xOnly:  []bool{false, false, true, true},
+			want:   "45ABD206E61E3DF2EC9E264A6FEC8292141A633C28586388235541F9ADE75435",
+		},
+		{
+			name:   "four alternating tweaks",
+			tweaks: []int{0, 1, 2, 3},
+			xOnly:  []bool{true, false, true, false},
+			want:   "B255FDCAC27B40C7CE7848E2D3B7BF5EA0ED756DA81565AC804CCCA3E1D5D239",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := crypto.NewPrivateKey(mustDecodeHex(t, musigSignKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+			keyAgg, err := crypto.MuSigKeyAgg(parsePublicKeys(t, tweakPubKeys, []int{1, 2, 0}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, idx := range tt.tweaks {
+				if keyAgg, err = keyAgg.ApplyTweak(mustDecodeHex(t, tweaks[idx]), tt.xOnly[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			session, err := crypto.NewMuSigSession(mustDecodeHex(t, musigSignAggNonce), keyAgg, mustDecodeHex(t, musigSignMsg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			psig, err := session.Sign(mustDecodeHex(t, musigSignSecNonce), key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(psig); !strings.EqualFold(got, tt.want) {
+				t.Fatalf("%s != %s", got, tt.want)
+			}
+			if !session.VerifyPartial(psig, mustDecodeHex(t, musigSignPubNonces[0]), key.Public()) {
+				t.Fatal("partial signature is invalid")
+			}
+		})
+	}
+}
+
+func TestMuSigAggregate(t *testing.T) {
+	t.Parallel()
+
+	// a subset of the cases of sig_agg_vectors.json
+	aggPubKeys := []string{
+		"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9",
+		"02D2DC6F5DF7C56ACF38C7FA0AE7A759AE30E19B37359DFDE015872324C7EF6E05",
+		"03C7FB101D97FF930ACD0C6760852EF64E69083DE0B06AC6335724754BB4B0522C",
+		"02352433B21E7E05D3B452B81CAE566E06D2E003ECE16D1074AABA4289E0E3D581",
+	}
+	aggPubNonces := []string{
+		"036E5EE6E28824029FEA3E8A9DDD2C8483F5AF98F7177C3AF3CB6F47CAF8D94AE9" +
+			"02DBA67E4A1F3680826172DA15AFB1A8CA85C7C5CC88900905C8DC8C328511B53E",
+		"03E4F798DA48A76EEC1C9CC5AB7A880FFBA201A5F064E627EC9CB0031D1D58FC51" +
+			"03E06180315C5A522B7EC7C08B69DCD721C313C940819296D0A7AB8E8795AC1F00",
+		"02C0068FD25523A31578B8077F24F78F5BD5F2422AFF47C1FADA0F36B3CEB6C7D2" +
+			"02098A55D1736AA5FCC21CF0729CCE852575C06C081125144763C2C4C4A05C09B6",
+		"023F7042046E0397822C4144A17F8B63D78748696A46C3B9F0A901D296EC3406C3" +
+			"02022B0B464292CF9751D699F10980AC764E6F671EFCA15069BBE62B0D1C62522A",
+	}
+	aggTweaks := []string{
+		"B511DA492182A91B0FFB9A98020D55F260AE86D7ECBD0399C7383D59A5F2AF7C",
+		"A815FE049EE3C5AAB66310477FBC8BCCCAC2F3395F59F921C364ACD78A2F48DC",
+		"75448A87274B056468B977BE06EB1E9F657577B7320B0A3376EA51FD420D18A8",
+	}
+	aggPartialSigs := []string{
+		"B15D2CD3C3D22B04DAE438CE653F6B4ECF042F42CFDED7C41B64AAF9B4AF53FB",
+		"6193D6AC61B354E9105BBDC8937A3454A6D705B6D57322A5A472A02CE99FCB64",
+		"9A87D3B79EC67228CB97878B76049B15DBD05B8158D17B5B9114D3C226887505",
+		"66F82EA90923689B855D36C6B7E032FB9970301481B99E01CDB4D6AC7C347A15",
+		"97B890A26C981DA8102D3BC294159D171D72810FDF7C6A691DEF02F0F7AF3FDC",
+		"53FA9E08BA5243CBCB0D797C5EE83BC6728E539EB76C2D0BF0F971EE4E909971",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141",
+	}
+	const aggMsg = "599C67EA410D005B9DA90817CF03ED3B1C868E4DA4EDF00A5880B0082C237869"
+
+	tests := []struct {
+		name     string
+		aggNonce string
+		nonces   []int
+		keys     []int
+		tweaks   []int
+		xOnly    []bool
+		psigs    []int
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "two signers",
+			aggNonce: "0341432722C5CD0268D829C702CF0D1CBCE57033EED201FD335191385227C3210C03D377F2D258B64AADC0E16F26462323D701D286046A2EA93365656AFD9875982B",
+			nonces:   []int{0, 1},
+			keys:     []int{0, 1},
+			psigs:    []int{0, 1},
+			want:     "041DA22223CE65C92C9A0D6C2CAC828AAF1EEE56304FEC371DDF91EBB2B9EF0912F1038025857FEDEB3FF696F8B99FA4BB2C5812F6095A2E0004EC99CE18DE1E",
+		},
+		{
+			name:     "other two signers",
+			aggNonce: "0224AFD36C902084058B51B5D36676BBA4DC97C775873768E58822F87FE437D792028CB15929099EEE2F5DAE404CD39357591BA32E9AF4E162B8D3E7CB5EFE31CB20",
+			nonces:   []int{0, 2},
+			keys:     []int{0, 2},
+			psigs:    []int{2, 3},
+			want:     "1069B67EC3D2F3C7C08291ACCB17A9C9B8F2819A52EB5DF8726E17E7D6B52E9F01800260A7E9DAC450F4BE522DE4CE12BA91AEAF2B4279219EF74BE1D286ADD9",
+		},
+		{
+			name:     "x-only and plain tweaks",
+			aggNonce: "02B5AD07AFCD99B6D92CB433FBD2A28FDEB98EAE2EB09B6014EF0F8197CD58403302E8616910F9293CF692C49F351DB86B25E352901F0E237BAFDA11F1C1CEF29FFD",
+			nonces:   []int{0, 3},
+			keys:     []int{0, 3},
+			tweaks:   []int{0, 1, 2},
+			xOnly:    []bool{true, false, true},
+			psigs:    []int{4, 5},
+			want:     "839B08820B681DBA8DAF4CC7B104E8F2638F9388F8D7A555DC17B6E6971D7426CE07BF6AB01F1DB50E4E33719295F4094572B79868E440FB3DEFD3FAC1DB589E",
+		},
+		{
+			name:     "partial signature out of range",
+			aggNonce: "02B5AD07AFCD99B6D92CB433FBD2A28FDEB98EAE2EB09B6014EF0F8197CD58403302E8616910F9293CF692C49F351DB86B25E352901F0E237BAFDA11F1C1CEF29FFD",
+			nonces:   []int{0, 3},
+			keys:     []int{0, 3},
+			tweaks:   []int{0, 1, 2},
+			xOnly:    []bool{true, false, true},
+			psigs:    []int{5, 6},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pubNonces := make([][]byte, len(tt.nonces))
+			for i, idx := range tt.nonces {
+				pubNonces[i] = mustDecodeHex(t, aggPubNonces[idx])
+			}
+			aggNonce, err := crypto.MuSigNonceAgg(pubNonces)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(aggNonce); !strings.EqualFold(got, tt.aggNonce) {
+				t.Fatalf("aggregate nonce %s != %s", got, tt.aggNonce)
+			}
+
+			keyAgg, err := crypto.MuSigKeyAgg(parsePublicKeys(t, aggPubKeys, tt.keys))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, idx := range tt.tweaks {
+				if keyAgg, err = keyAgg.ApplyTweak(mustDecodeHex(t, aggTweaks[idx]), tt.xOnly[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			session, err := crypto.NewMuSigSession(aggNonce, keyAgg, mustDecodeHex(t, aggMsg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			psigs := make([][]byte, len(tt.psigs))
+			for i, idx := range tt.psigs {
+				psigs[i] = mustDecodeHex(t, aggPartialSigs[idx])
+			}
+
+			sig, err := session.Aggregate(psigs)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "signer 1") {
+					t.Fatalf("expected error for signer 1, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(sig.Serialize()); !strings.EqualFold(got, tt.want) {
+				t.Fatalf("%s != %s", got, tt.want)
+			}
+			if !crypto.SchnorrVerify(keyAgg.PublicKey(), mustDecodeHex(t, aggMsg), sig) {
+				t.Fatal("aggregate signature is invalid")
+			}
+		})
+	}
+}
+
+func TestMuSigSignAndAggregate(t *testing.T) {
+	t.Parallel()
+
+	const signers = 3
+	keys := make([]*crypto.PrivateKey, signers)
+	pubs := make([]*crypto.PublicKey, signers)
+	for i := range keys {
+		key, err := crypto.GeneratePrivateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+		pubs[i] = key.Public()
+	}
+
+	keyAgg, err := crypto.MuSigKeyAgg(crypto.MuSigKeySort(pubs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a BIP 32 style plain tweak followed by a Taproot style x-only tweak
+	for i, xOnly := range []bool{false, true} {
+		tweak := bytes.Repeat([]byte{byte(i + 1)}, crypto.TweakSize)
+		if keyAgg, err = keyAgg.ApplyTweak(tweak, xOnly); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	msg := []byte("single-key-looking multi-signature")
+	secNonces := make([][]byte, signers)
+	pubNonces := make([][]byte, signers)
+	for i := range keys {
+		if secNonces[i], pubNonces[i], err = crypto.MuSigNonceGen(rand.Reader, keys[i], pubs[i], nil, msg, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	aggNonce, err := crypto.MuSigNonceAgg(pubNonces)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := crypto.NewMuSigSession(aggNonce, keyAgg, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	psigs := make([][]byte, signers)
+	for i := range keys {
+		if psigs[i], err = session.Sign(secNonces[i], keys[i]); err != nil {
+			t.Fatal(err)
+		}
+		for j := range keys {
+			if got := session.VerifyPartial(psigs[i], pubNonces[j], pubs[j]); got != (i == j) {
+				t.Fatalf("partial signature %d with signer %d: %t != %t", i, j, got, i == j)
+			}
+		}
+	}
+
+	sig, err := session.Aggregate(psigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !crypto.SchnorrVerify(keyAgg.PublicKey(), msg, sig) {
+		t.Fatalf("signature %x is invalid", sig.Serialize())
+	}
+
+	psigs[1] = bytes.Repeat([]byte{0xff}, crypto.MuSigPartialSigSize)
+	if _, err := session.Aggregate(psigs); err == nil {
+		t.Fatal("expected error for an out of range partial signature")
+	}
+}
@@ -0,0 +1,142 @@
+package crypto
+
+const (
+	// pippengerMinPoints is the number of points from which Pippenger's
+	// method is faster than Strauss' one.
+	pippengerMinPoints = 16
+
+	// pippengerMaxWindowBits bounds the window width of Pippenger's method,
+	// which takes 2^(c-1) buckets for c-bit windows.
+	pippengerMaxWindowBits = 16
+)
+
+// mulMultiAffine sets p = k[0]*a[0] + ... + k[m-1]*a[m-1] for affine points
+// using Pippenger's bucket method. The running time depends on the scalars,
+// so it must only be used with public scalars.
+//
+// Every product is split with the GLV method first, and the halves are
+// recoded into signed c-bit digits. For every window, starting with the most
+// significant one, each point is added to the bucket of the absolute value
+// of its digit, negated if the digit is negative, and the buckets B_j are
+// summed up as 1*B_1 + 2*B_2 + ... with two running sums. This takes about
+// m*b/c mixed additions and (b/c)*2^c additions for b-bit halves, while
+// Strauss' method takes about m*b/4 additions besides the tables, so it wins
+// by far for the thousands of points of a block. Fewer points than
+// pippengerMinPoints are left to Strauss' method of mulMulti.
+func (p *jacobianPoint) mulMultiAffine(a []affinePoint, k []scalarVal) *jacobianPoint {
+	if len(a) < pippengerMinPoints {
+		jac := make([]jacobianPoint, len(a))
+		for i := range a {
+			jac[i].x, jac[i].y = a[i].x, a[i].y
+			jac[i].z.setInt(1)
+		}
+		return p.mulMulti(jac, k)
+	}
+
+	points := make([]affinePoint, 2*len(a))
+	halves := make([]scalarVal, 2*len(a))
+	bitLen := 0
+	for i := range a {
+		k1, k2 := splitScalar(k[i].big())
+
+		// phi(x,y) = (beta*x,y)
+		points[2*i] = a[i]
+		points[2*i+1].x.mul(&a[i].x, &endoBeta)
+		points[2*i+1].y = a[i].y
+		if k1.Sign() < 0 {
+			points[2*i].y.neg(&points[2*i].y)
+		}
+		if k2.Sign() < 0 {
+			points[2*i+1].y.neg(&points[2*i+1].y)
+		}
+
+		halves[2*i].setBig(k1.Abs(k1))
+		halves[2*i+1].setBig(k2.Abs(k2))
+		if k1.BitLen() > bitLen {
+			bitLen = k1.BitLen()
+		}
+		if k2.BitLen() > bitLen {
+			bitLen = k2.BitLen()
+		}
+	}
+
+	c := pippengerWindowBits(len(points), bitLen)
+	// the top window only holds the carry of the signed recoding
+	windows := bitLen/c + 1
+	digits := make([]int32, len(points)*windows)
+	for i := range halves {
+		recodeSigned(digits[i*windows:(i+1)*windows], &halves[i], c)
+	}
+
+	buckets := make([]jacobianPoint, 1<<(c-1))
+	var res jacobianPoint
+	res.setInfinity()
+	for w := windows - 1; w >= 0; w-- {
+		for i := 0; i < c; i++ {
+			res.double(&res)
+		}
+
+		for j := range buckets {
+			buckets[j].setInfinity()
+		}
+		for i := range points {
+			switch d := digits[i*windows+w]; {
+			case d > 0:
+				buckets[d-1].addAffine(&buckets[d-1], &points[i])
+			case d < 0:
+				neg := affinePoint{x: points[i].x}
+				neg.y.neg(&points[i].y)
+				buckets[-d-1].addAffine(&buckets[-d-1], &neg)
+			}
+		}
+
+		// running = B_j + ... + B_top, so the sum of the running sums is
+		// 1*B_1 + 2*B_2 + ...
+		var running, sum jacobianPoint
+		running.setInfinity()
+		sum.setInfinity()
+		for j := len(buckets) - 1; j >= 0; j-- {
+			running.add(&running, &buckets[j])
+			sum.add(&sum, &running)
+		}
+		res.add(&res, &sum)
+	}
+
+	*p = res
+	return p
+}
+
+// pippengerWindowBits returns the window width with the least estimated
+// number of point operations for the number of points and the scalar length:
+// one addition per point, two per bucket and the doublings in every window.
+func pippengerWindowBits(points, bitLen int) int {
+	best, bestCost := 2, -1
+	for c := 2; c <= pippengerMaxWindowBits; c++ {
+		cost := (bitLen/c + 1) * (points + 1<<c + c)
+		if bestCost < 0 || cost < bestCost {
+			best, bestCost = c, cost
+		}
+	}
+	return best
+}
+
+// recodeSigned sets the digits to the signed c-bit windows of k, each in
+// -2^(c-1)+1..2^(c-1), whose sum of d_w*2^(c*w) equals k. A window above
+// 2^(c-1) is taken as the negative difference to 2^c with a carry into the
+// next window, so there must be enough digits to hold the last carry.
+func recodeSigned(digits []int32, k *scalarVal, c int) {
+	var carry uint64
+	for w := range digits {
+		v := carry
+		if bit := w * c; bit < 256 {
+			v += scalarWindow(k, bit, c)
+		}
+
+		carry = 0
+		if v > 1<<(c-1) {
+			v -= 1 << c
+			carry = 1
+		}
+		digits[w] = int32(int64(v))
+	}
+}
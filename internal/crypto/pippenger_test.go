@@ -0,0 +1,85 @@
+package crypto
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestMulMultiAffine(t *testing.T) {
+	t.Parallel()
+
+	n := secp256k1.params.N
+	for _, size := range []int{1, pippengerMinPoints - 1, pippengerMinPoints, 100} {
+		points := make([]affinePoint, size)
+		jacobian := make([]jacobianPoint, size)
+		scalars := make([]scalarVal, size)
+		for i := range points {
+			var k scalarVal
+			k.setBig(randomScalarInt(t))
+			var p jacobianPoint
+			x, y := p.mulBase(&k).toAffine()
+
+			// repeated and opposite points end up in the same buckets
+			switch {
+			case i%5 == 1:
+				x, y = jacobian[i-1].toAffine()
+			case i%5 == 2:
+				x, y = jacobian[i-2].toAffine()
+				y = new(big.Int).Sub(secp256k1.params.P, y)
+			}
+			points[i].x.setBig(x)
+			points[i].y.setBig(y)
+			jacobian[i].setAffine(x, y)
+
+			switch i % 4 {
+			case 0:
+				scalars[i].setBig(randomScalarInt(t))
+			case 1:
+				scalars[i].setInt(1)
+			case 2:
+				scalars[i].setBig(new(big.Int).Sub(n, big.NewInt(1)))
+			}
+		}
+
+		var want jacobianPoint
+		want.setInfinity()
+		for i := range jacobian {
+			var term jacobianPoint
+			want.add(&want, term.mulVarTime(&jacobian[i], &scalars[i]))
+		}
+
+		var got jacobianPoint
+		gotX, gotY := got.mulMultiAffine(points, scalars).toAffine()
+		wantX, wantY := want.toAffine()
+		if !equalAffine(gotX, gotY, wantX, wantY) {
+			t.Fatalf("size %d: (%x,%x) != (%x,%x)", size, gotX, gotY, wantX, wantY)
+		}
+	}
+}
+
+func TestRecodeSigned(t *testing.T) {
+	t.Parallel()
+
+	for c := 2; c <= pippengerMaxWindowBits; c++ {
+		for i := 0; i < 8; i++ {
+			k := randomScalarInt(t)
+			var s scalarVal
+			s.setBig(k)
+
+			digits := make([]int32, k.BitLen()/c+1)
+			recodeSigned(digits, &s, c)
+
+			got := new(big.Int)
+			for w := len(digits) - 1; w >= 0; w-- {
+				if d := digits[w]; d > 1<<(c-1) || d <= -(1<<(c-1)) {
+					t.Fatalf("c=%d: digit %d is out of range", c, d)
+				}
+				got.Lsh(got, uint(c))
+				got.Add(got, big.NewInt(int64(digits[w])))
+			}
+			if got.Cmp(k) != 0 {
+				t.Fatalf("c=%d: %x != %x", c, got, k)
+			}
+		}
+	}
+}
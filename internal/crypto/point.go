@@ -0,0 +1,296 @@
+package crypto
+
+import "math/big"
+
+// jacobianPoint describes a point (X,Y,Z) in Jacobian coordinates which
+// represents the affine point (X/Z^2, Y/Z^3). The point at infinity has
+// Z = 0.
+type jacobianPoint struct {
+	x, y, z fieldVal
+}
+
+// affinePoint describes a finite point (x,y) in affine coordinates.
+type affinePoint struct {
+	x, y fieldVal
+}
+
+// cmov sets p to q if flag is 1 and leaves it unchanged if flag is 0.
+func (p *affinePoint) cmov(q *affinePoint, flag uint64) *affinePoint {
+	p.x.cmov(&q.x, flag)
+	p.y.cmov(&q.y, flag)
+	return p
+}
+
+// setInfinity sets p to the point at infinity.
+func (p *jacobianPoint) setInfinity() *jacobianPoint {
+	p.x.setInt(1)
+	p.y.setInt(1)
+	p.z.setInt(0)
+	return p
+}
+
+// isInfinity reports whether p is the point at infinity.
+func (p *jacobianPoint) isInfinity() bool {
+	return p.z.isZero() == 1
+}
+
+// setAffine sets p to the affine point (x,y) where nil coordinates denote
+// the point at infinity.
+func (p *jacobianPoint) setAffine(x, y *big.Int) *jacobianPoint {
+	if x == nil {
+		return p.setInfinity()
+	}
+
+	p.x.setBig(x)
+	p.y.setBig(y)
+	p.z.setInt(1)
+	return p
+}
+
+// toAffine converts p to affine coordinates with a single inversion of Z.
+func (p *jacobianPoint) toAffine() (x, y *big.Int) {
+	if p.isInfinity() {
+		return
+	}
+
+	var ax, ay fieldVal
+	p.affineCoords(&ax, &ay)
+	return ax.big(), ay.big()
+}
+
+// affineCoords sets x and y to the affine coordinates of a finite point.
+func (p *jacobianPoint) affineCoords(x, y *fieldVal) {
+	var zInv, zInv2, zInv3 fieldVal
+	zInv.inverse(&p.z)
+	zInv2.sqr(&zInv)
+	zInv3.mul(&zInv2, &zInv)
+	x.mul(&p.x, &zInv2)
+	y.mul(&p.y, &zInv3)
+}
+
+// cmov sets p to q if flag is 1 and leaves it unchanged if flag is 0.
+func (p *jacobianPoint) cmov(q *jacobianPoint, flag uint64) *jacobianPoint {
+	p.x.cmov(&q.x, flag)
+	p.y.cmov(&q.y, flag)
+	p.z.cmov(&q.z, flag)
+	return p
+}
+
+// neg sets p = -a.
+func (p *jacobianPoint) neg(a *jacobianPoint) *jacobianPoint {
+	p.x = a.x
+	p.y.neg(&a.y)
+	p.z = a.z
+	return p
+}
+
+// double sets p = 2*a using the dbl-2009-l formulas for curves with a = 0.
+// The point at infinity stays at infinity since Z3 = 2*Y1*Z1 = 0.
+func (p *jacobianPoint) double(a *jacobianPoint) *jacobianPoint {
+	var t0, t1, t2, d, e, f, x3, y3, z3 fieldVal
+
+	// A = X1^2, B = Y1^2, C = B^2
+	t0.sqr(&a.x)
+	t1.sqr(&a.y)
+	t2.sqr(&t1)
+
+	// D = 2*((X1+B)^2-A-C)
+	d.add(&a.x, &t1)
+	d.sqr(&d)
+	d.sub(&d, &t0)
+	d.sub(&d, &t2)
+	d.double(&d)
+
+	// E = 3*A, F = E^2
+	e.double(&t0)
+	e.add(&e, &t0)
+	f.sqr(&e)
+
+	// X3 = F-2*D
+	x3.double(&d)
+	x3.sub(&f, &x3)
+
+	// Y3 = E*(D-X3)-8*C
+	y3.sub(&d, &x3)
+	y3.mul(&y3, &e)
+	t2.double(&t2)
+	t2.double(&t2)
+	t2.double(&t2)
+	y3.sub(&y3, &t2)
+
+	// Z3 = 2*Y1*Z1
+	z3.mul(&a.y, &a.z)
+	z3.double(&z3)
+
+	p.x, p.y, p.z = x3, y3, z3
+	return p
+}
+
+// add sets p = a+b handling the point at infinity as well as equal and
+// opposite points. The running time depends on the points.
+func (p *jacobianPoint) add(a, b *jacobianPoint) *jacobianPoint {
+	if a.isInfinity() {
+		*p = *b
+		return p
+	}
+	if b.isInfinity() {
+		*p = *a
+		return p
+	}
+
+	sum, h, r := addJacobian(a, b)
+	if h.isZero() == 1 {
+		// both points share the same x, so they are either equal or
+		// opposite to each other
+		if r.isZero() == 1 {
+			return p.double(a)
+		}
+		return p.setInfinity()
+	}
+
+	*p = sum
+	return p
+}
+
+// addConstTime sets p = a+b in constant time where either point may be at
+// infinity. The caller must guarantee that a != ±b whenever both points are
+// finite.
+func (p *jacobianPoint) addConstTime(a, b *jacobianPoint) *jacobianPoint {
+	aInf := a.z.isZero()
+	bInf := b.z.isZero()
+
+	sum, _, _ := addJacobian(a, b)
+	sum.cmov(b, aInf)
+	sum.cmov(a, bInf)
+	*p = sum
+	return p
+}
+
+// addAffine sets p = a+b for an affine point b handling the point at
+// infinity as well as equal and opposite points. The running time depends
+// on the points.
+func (p *jacobianPoint) addAffine(a *jacobianPoint, b *affinePoint) *jacobianPoint {
+	if a.isInfinity() {
+		p.x, p.y = b.x, b.y
+		p.z.setInt(1)
+		return p
+	}
+
+	sum, h, r := addJacobianAffine(a, b)
+	if h.isZero() == 1 {
+		// both points share the same x, so they are either equal or
+		// opposite to each other
+		if r.isZero() == 1 {
+			return p.double(a)
+		}
+		return p.setInfinity()
+	}
+
+	*p = sum
+	return p
+}
+
+// addMixed sets p = a+b for an affine point b in constant time. The result
+// is only meaningful if a is finite and a != ±b.
+func (p *jacobianPoint) addMixed(a *jacobianPoint, b *affinePoint) *jacobianPoint {
+	*p, _, _ = addJacobianAffine(a, b)
+	return p
+}
+
+// addJacobianAffine returns a+b for an affine point b using the
+// madd-2007-bl formulas, which save four multiplications over addJacobian,
+// together with the values H and r defined as there.
+func addJacobianAffine(a *jacobianPoint, b *affinePoint) (sum jacobianPoint, h, r fieldVal) {
+	var z1z1, u2, s2, hh, i, j, v fieldVal
+
+	// Z1Z1 = Z1^2, U2 = X2*Z1Z1, S2 = Y2*Z1*Z1Z1
+	z1z1.sqr(&a.z)
+	u2.mul(&b.x, &z1z1)
+	s2.mul(&b.y, &a.z)
+	s2.mul(&s2, &z1z1)
+
+	// H = U2-X1, HH = H^2, I = 4*HH, J = H*I
+	h.sub(&u2, &a.x)
+	hh.sqr(&h)
+	i.double(&hh)
+	i.double(&i)
+	j.mul(&h, &i)
+
+	// r = 2*(S2-Y1), V = X1*I
+	r.sub(&s2, &a.y)
+	r.double(&r)
+	v.mul(&a.x, &i)
+
+	// X3 = r^2-J-2*V
+	sum.x.sqr(&r)
+	sum.x.sub(&sum.x, &j)
+	sum.x.sub(&sum.x, &v)
+	sum.x.sub(&sum.x, &v)
+
+	// Y3 = r*(V-X3)-2*Y1*J
+	sum.y.sub(&v, &sum.x)
+	sum.y.mul(&sum.y, &r)
+	j.mul(&j, &a.y)
+	j.double(&j)
+	sum.y.sub(&sum.y, &j)
+
+	// Z3 = (Z1+H)^2-Z1Z1-HH
+	sum.z.add(&a.z, &h)
+	sum.z.sqr(&sum.z)
+	sum.z.sub(&sum.z, &z1z1)
+	sum.z.sub(&sum.z, &hh)
+	return
+}
+
+// addJacobian returns a+b using the add-2007-bl formulas together with the
+// values H = U2-U1 and r = 2*(S2-S1) which are both zero if a = b and only
+// H is zero if a = -b.
+func addJacobian(a, b *jacobianPoint) (sum jacobianPoint, h, r fieldVal) {
+	var z1z1, z2z2, u1, u2, s1, s2, i, j, v fieldVal
+
+	// Z1Z1 = Z1^2, Z2Z2 = Z2^2
+	z1z1.sqr(&a.z)
+	z2z2.sqr(&b.z)
+
+	// U1 = X1*Z2Z2, U2 = X2*Z1Z1
+	u1.mul(&a.x, &z2z2)
+	u2.mul(&b.x, &z1z1)
+
+	// S1 = Y1*Z2*Z2Z2, S2 = Y2*Z1*Z1Z1
+	s1.mul(&a.y, &b.z)
+	s1.mul(&s1, &z2z2)
+	s2.mul(&b.y, &a.z)
+	s2.mul(&s2, &z1z1)
+
+	// H = U2-U1, I = (2*H)^2, J = H*I
+	h.sub(&u2, &u1)
+	i.double(&h)
+	i.sqr(&i)
+	j.mul(&h, &i)
+
+	// r = 2*(S2-S1), V = U1*I
+	r.sub(&s2, &s1)
+	r.double(&r)
+	v.mul(&u1, &i)
+
+	// X3 = r^2-J-2*V
+	sum.x.sqr(&r)
+	sum.x.sub(&sum.x, &j)
+	sum.x.sub(&sum.x, &v)
+	sum.x.sub(&sum.x, &v)
+
+	// Y3 = r*(V-X3)-2*S1*J
+	sum.y.sub(&v, &sum.x)
+	sum.y.mul(&sum.y, &r)
+	s1.mul(&s1, &j)
+	s1.double(&s1)
+	sum.y.sub(&sum.y, &s1)
+
+	// Z3 = ((Z1+Z2)^2-Z1Z1-Z2Z2)*H
+	sum.z.add(&a.z, &b.z)
+	sum.z.sqr(&sum.z)
+	sum.z.sub(&sum.z, &z1z1)
+	sum.z.sub(&sum.z, &z2z2)
+	sum.z.mul(&sum.z, &h)
+	return
+}
@@ -0,0 +1,83 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"math/big"
+)
+
+// nonceGenerator derives deterministic nonces as specified by RFC 6979
+// section 3.2 using HMAC-SHA256.
+//
+// Candidates are produced in sequence so that a caller may ask for the next
+// one whenever the current nonce leads to an unusable signature.
+type nonceGenerator struct {
+	k, v []byte
+	n    *big.Int
+}
+
+// newNonceGenerator seeds the HMAC-DRBG with the private key d and the
+// message hash. Optional extra data is appended to the seed as described in
+// section 3.6 of RFC 6979.
+func newNonceGenerator(d *big.Int, hash []byte, extra []byte) *nonceGenerator {
+	n := secp256k1.Params().N
+	qlen := n.BitLen()
+	rlen := (qlen + 7) / 8
+
+	x := d.FillBytes(make([]byte, rlen))
+	h := bitsToInt(hash, qlen)
+	h.Mod(h, n)
+	seed := append(append(x, h.FillBytes(make([]byte, rlen))...), extra...)
+
+	g := &nonceGenerator{
+		k: make([]byte, sha256.Size),
+		v: make([]byte, sha256.Size),
+		n: n,
+	}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+
+	g.k = hmacSHA256(g.k, g.v, []byte{0x00}, seed)
+	g.v = hmacSHA256(g.k, g.v)
+	g.k = hmacSHA256(g.k, g.v, []byte{0x01}, seed)
+	g.v = hmacSHA256(g.k, g.v)
+	return g
+}
+
+// next returns the next nonce candidate in the range [1, n-1].
+func (g *nonceGenerator) next() *big.Int {
+	for {
+		g.v = hmacSHA256(g.k, g.v)
+		k := bitsToInt(g.v, g.n.BitLen())
+		if isValidScalar(k) {
+			// prepare the state for the case when the caller rejects
+			// the nonce returned on this step
+			g.k = hmacSHA256(g.k, g.v, []byte{0x00})
+			g.v = hmacSHA256(g.k, g.v)
+			return k
+		}
+
+		g.k = hmacSHA256(g.k, g.v, []byte{0x00})
+		g.v = hmacSHA256(g.k, g.v)
+	}
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the concatenated data under the key.
+func hmacSHA256(key []byte, data ...[]byte) []byte {
+	m := hmac.New(sha256.New, key)
+	for _, d := range data {
+		m.Write(d)
+	}
+	return m.Sum(nil)
+}
+
+// bitsToInt converts a byte slice to an integer keeping only its leftmost
+// qlen bits.
+func bitsToInt(b []byte, qlen int) *big.Int {
+	x := new(big.Int).SetBytes(b)
+	if blen := len(b) * 8; blen > qlen {
+		x.Rsh(x, uint(blen-qlen))
+	}
+	return x
+}
@@ -0,0 +1,228 @@
+package crypto
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// scalarVal describes an integer modulo the group order n as four 64-bit
+// limbs in little-endian order. Values are always kept fully reduced.
+//
+// Like field elements, scalars are processed in constant time, so they are
+// used for arithmetic on private keys and nonces.
+type scalarVal [4]uint64
+
+// scalarN is the group order n.
+var scalarN = scalarVal{0xbfd25e8cd0364141, 0xbaaedce6af48a03b, 0xfffffffffffffffe, 0xffffffffffffffff}
+
+// scalarReductionConst is c = 2^256 - n in three limbs. It is 129 bits long,
+// so a 512-bit product needs several folds of H*2^256 + L to L + H*c.
+var scalarReductionConst = [3]uint64{0x402da1732fc9bebf, 0x4551231950b75fc4, 0x1}
+
+// scalarNMinus2 is the exponent n-2 used to compute inverses according to
+// Fermat's little theorem.
+var scalarNMinus2 = scalarVal{0xbfd25e8cd036413f, 0xbaaedce6af48a03b, 0xfffffffffffffffe, 0xffffffffffffffff}
+
+// setInt sets s to a small integer.
+func (s *scalarVal) setInt(v uint64) *scalarVal {
+	*s = scalarVal{v}
+	return s
+}
+
+// setBytes sets s to the 32-byte big-endian value reduced modulo n and
+// reports whether the value overflowed n.
+func (s *scalarVal) setBytes(b *[32]byte) (*scalarVal, bool) {
+	for i := 0; i < 4; i++ {
+		j := 32 - 8*(i+1)
+		s[i] = uint64(b[j])<<56 | uint64(b[j+1])<<48 | uint64(b[j+2])<<40 | uint64(b[j+3])<<32 |
+			uint64(b[j+4])<<24 | uint64(b[j+5])<<16 | uint64(b[j+6])<<8 | uint64(b[j+7])
+	}
+	overflow := s.reduce(0)
+	return s, overflow == 1
+}
+
+// setByteSlice sets s to a big-endian value of any length reduced modulo n.
+func (s *scalarVal) setByteSlice(b []byte) *scalarVal {
+	if len(b) > 32 {
+		// oversized input never holds a well-formed secret, so it is
+		// reduced in variable time
+		b = new(big.Int).Mod(new(big.Int).SetBytes(b), secp256k1.params.N).Bytes()
+	}
+
+	var buf [32]byte
+	copy(buf[32-len(b):], b)
+	s.setBytes(&buf)
+	return s
+}
+
+// setBig sets s to the integer v reduced modulo n.
+func (s *scalarVal) setBig(v *big.Int) *scalarVal {
+	if v.Sign() < 0 {
+		v = new(big.Int).Mod(v, secp256k1.params.N)
+	}
+	return s.setByteSlice(v.Bytes())
+}
+
+// bytes returns s as 32 bytes in big-endian form.
+func (s *scalarVal) bytes() [32]byte {
+	var b [32]byte
+	for i := 0; i < 4; i++ {
+		j := 32 - 8*(i+1)
+		for k := 0; k < 8; k++ {
+			b[j+k] = byte(s[i] >> (56 - 8*k))
+		}
+	}
+	return b
+}
+
+// big returns s as an integer.
+func (s *scalarVal) big() *big.Int {
+	b := s.bytes()
+	return new(big.Int).SetBytes(b[:])
+}
+
+// isZero returns 1 if s is zero and 0 otherwise.
+func (s *scalarVal) isZero() uint64 {
+	v := s[0] | s[1] | s[2] | s[3]
+	return 1 ^ (v|-v)>>63
+}
+
+// bit returns the bit of s at the position i.
+func (s *scalarVal) bit(i int) uint64 {
+	return (s[i/64] >> (i % 64)) & 1
+}
+
+// cmov sets s to t if flag is 1 and leaves it unchanged if flag is 0.
+func (s *scalarVal) cmov(t *scalarVal, flag uint64) *scalarVal {
+	mask := -flag
+	for i := range s {
+		s[i] ^= (s[i] ^ t[i]) & mask
+	}
+	return s
+}
+
+// reduce subtracts n from the 257-bit value carry*2^256 + s if it is not
+// less than n and returns 1 if the subtraction took place.
+func (s *scalarVal) reduce(carry uint64) uint64 {
+	var t scalarVal
+	var borrow uint64
+	t[0], borrow = bits.Sub64(s[0], scalarN[0], 0)
+	t[1], borrow = bits.Sub64(s[1], scalarN[1], borrow)
+	t[2], borrow = bits.Sub64(s[2], scalarN[2], borrow)
+	t[3], borrow = bits.Sub64(s[3], scalarN[3], borrow)
+
+	flag := carry | (borrow ^ 1)
+	s.cmov(&t, flag)
+	return flag
+}
+
+// add sets s = a + b.
+func (s *scalarVal) add(a, b *scalarVal) *scalarVal {
+	var carry uint64
+	s[0], carry = bits.Add64(a[0], b[0], 0)
+	s[1], carry = bits.Add64(a[1], b[1], carry)
+	s[2], carry = bits.Add64(a[2], b[2], carry)
+	s[3], carry = bits.Add64(a[3], b[3], carry)
+	s.reduce(carry)
+	return s
+}
+
+// neg sets s = -a.
+func (s *scalarVal) neg(a *scalarVal) *scalarVal {
+	var t scalarVal
+	var borrow uint64
+	t[0], borrow = bits.Sub64(scalarN[0], a[0], 0)
+	t[1], borrow = bits.Sub64(scalarN[1], a[1], borrow)
+	t[2], borrow = bits.Sub64(scalarN[2], a[2], borrow)
+	t[3], _ = bits.Sub64(scalarN[3], a[3], borrow)
+
+	// n - 0 must give 0 rather than n
+	var zero scalarVal
+	t.cmov(&zero, a.isZero())
+	*s = t
+	return s
+}
+
+// sub sets s = a - b.
+func (s *scalarVal) sub(a, b *scalarVal) *scalarVal {
+	var nb scalarVal
+	nb.neg(b)
+	return s.add(a, &nb)
+}
+
+// mul sets s = a * b.
+func (s *scalarVal) mul(a, b *scalarVal) *scalarVal {
+	var t [8]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(a[i], b[j])
+			var c uint64
+			lo, c = bits.Add64(lo, t[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			t[i+j] = lo
+			carry = hi
+		}
+		t[i+4] = carry
+	}
+	return s.reduceWide(&t)
+}
+
+// reduceWide sets s to the 512-bit value t reduced modulo n.
+//
+// Every fold replaces H*2^256 + L with L + H*c. Starting from 512 bits the
+// value shrinks to 386, 260 and 257 bits, and a fourth fold leaves at most a
+// 256-bit value. The folds always run over all limbs to keep the running
+// time independent of the value.
+func (s *scalarVal) reduceWide(t *[8]uint64) *scalarVal {
+	r := *t
+	for fold := 0; fold < 4; fold++ {
+		var next [8]uint64
+		copy(next[:4], r[:4])
+
+		for i := 0; i < 4; i++ {
+			var carry uint64
+			for j := 0; j < 3; j++ {
+				hi, lo := bits.Mul64(r[4+i], scalarReductionConst[j])
+				var c uint64
+				lo, c = bits.Add64(lo, next[i+j], 0)
+				hi += c
+				lo, c = bits.Add64(lo, carry, 0)
+				hi += c
+				next[i+j] = lo
+				carry = hi
+			}
+			for k := i + 3; k < 8; k++ {
+				next[k], carry = bits.Add64(next[k], carry, 0)
+			}
+		}
+		r = next
+	}
+
+	copy(s[:], r[:4])
+	s.reduce(0)
+	return s
+}
+
+// exp sets s = a^e. The running time depends on the exponent only, which
+// is always a public constant.
+func (s *scalarVal) exp(a, e *scalarVal) *scalarVal {
+	base := *a
+	var res scalarVal
+	res.setInt(1)
+	for i := 255; i >= 0; i-- {
+		res.mul(&res, &res)
+		if e.bit(i) == 1 {
+			res.mul(&res, &base)
+		}
+	}
+	*s = res
+	return s
+}
+
+// inverse sets s = a^-1 = a^(n-2). The inverse of zero is zero.
+func (s *scalarVal) inverse(a *scalarVal) *scalarVal {
+	return s.exp(a, &scalarNMinus2)
+}
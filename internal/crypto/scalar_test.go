@@ -0,0 +1,91 @@
+package crypto
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestScalarVal(t *testing.T) {
+	t.Parallel()
+
+	n := secp256k1.params.N
+	nMinus1 := new(big.Int).Sub(n, big.NewInt(1))
+	edges := []*big.Int{big.NewInt(0), big.NewInt(1), nMinus1, new(big.Int).Rsh(n, 1)}
+
+	var ints []*big.Int
+	ints = append(ints, edges...)
+	for i := 0; i < 32; i++ {
+		ints = append(ints, randomScalarInt(t))
+	}
+
+	mod := func(v *big.Int) *big.Int { return v.Mod(v, n) }
+	for _, a := range ints {
+		for _, b := range append(edges, ints[len(ints)-1]) {
+			var sa, sb, got scalarVal
+			sa.setBig(a)
+			sb.setBig(b)
+
+			if want := mod(new(big.Int).Add(a, b)); got.add(&sa, &sb).big().Cmp(want) != 0 {
+				t.Fatalf("%x+%x: %x != %x", a, b, got.big(), want)
+			}
+			if want := mod(new(big.Int).Sub(a, b)); got.sub(&sa, &sb).big().Cmp(want) != 0 {
+				t.Fatalf("%x-%x: %x != %x", a, b, got.big(), want)
+			}
+			if want := mod(new(big.Int).Mul(a, b)); got.mul(&sa, &sb).big().Cmp(want) != 0 {
+				t.Fatalf("%x*%x: %x != %x", a, b, got.big(), want)
+			}
+		}
+
+		var sa, got scalarVal
+		sa.setBig(a)
+		if want := mod(new(big.Int).Neg(a)); got.neg(&sa).big().Cmp(want) != 0 {
+			t.Fatalf("-%x: %x != %x", a, got.big(), want)
+		}
+		if a.Sign() != 0 {
+			want := new(big.Int).ModInverse(a, n)
+			if got.inverse(&sa).big().Cmp(want) != 0 {
+				t.Fatalf("%x^-1: %x != %x", a, got.big(), want)
+			}
+		}
+	}
+
+	t.Run("reduction constant", func(t *testing.T) {
+		c := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), n)
+		var want [3]uint64
+		for i := range want {
+			want[i] = new(big.Int).Rsh(c, uint(64*i)).Uint64()
+		}
+		if scalarReductionConst != want {
+			t.Fatalf("%x != %x", scalarReductionConst, want)
+		}
+	})
+
+	t.Run("set overflowing bytes", func(t *testing.T) {
+		var b [32]byte
+		n.FillBytes(b[:])
+		b[31]++
+
+		var s scalarVal
+		if _, overflow := s.setBytes(&b); !overflow || s.big().Int64() != 1 {
+			t.Fatalf("%x, overflow %t", s.big(), overflow)
+		}
+	})
+
+	t.Run("set oversized bytes", func(t *testing.T) {
+		v := new(big.Int).Lsh(nMinus1, 64)
+		var s scalarVal
+		if want := mod(new(big.Int).Set(v)); s.setByteSlice(v.Bytes()).big().Cmp(want) != 0 {
+			t.Fatalf("%x != %x", s.big(), want)
+		}
+	})
+}
+
+func BenchmarkScalarMul(b *testing.B) {
+	var x, y scalarVal
+	x.setBig(randomScalarInt(b))
+	y.setBig(randomScalarInt(b))
+
+	for i := 0; i < b.N; i++ {
+		x.mul(&x, &y)
+	}
+}
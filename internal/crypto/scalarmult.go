@@ -0,0 +1,57 @@
+package crypto
+
+// scalarWindowBits is the width of the window used by the constant-time
+// scalar multiplication.
+const scalarWindowBits = 4
+
+// mulConstTime sets p = k*a in time that does not depend on the value of k,
+// which makes it suitable for secret scalars.
+//
+// Here we use the fixed-window method: the multiples 0..15 of the point are
+// precomputed and for every 4-bit window of k the accumulator is doubled
+// four times and a multiple is added. The multiple is selected by scanning
+// the whole table with conditional moves, and the window loop always runs
+// over all 256 bits of k.
+//
+// Since k is reduced modulo n, the accumulator 16m*P and the multiple j*P
+// can never be equal or opposite finite points: that would require
+// 16m ± j = 0 modulo n while 16m + j never exceeds k < n.
+func (p *jacobianPoint) mulConstTime(a *jacobianPoint, k *scalarVal) *jacobianPoint {
+	var table [1 << scalarWindowBits]jacobianPoint
+	table[0].setInfinity()
+	table[1] = *a
+	for i := 2; i < len(table); i++ {
+		// the table is built from the public point only
+		if i%2 == 0 {
+			table[i].double(&table[i/2])
+		} else {
+			table[i].add(&table[i-1], &table[1])
+		}
+	}
+
+	var res jacobianPoint
+	res.setInfinity()
+	for w := 256/scalarWindowBits - 1; w >= 0; w-- {
+		for i := 0; i < scalarWindowBits; i++ {
+			res.double(&res)
+		}
+
+		bit := w * scalarWindowBits
+		idx := (k[bit/64] >> (bit % 64)) & (1<<scalarWindowBits - 1)
+
+		var sel jacobianPoint
+		for j := range table {
+			sel.cmov(&table[j], isEqualWord(uint64(j), idx))
+		}
+		res.addConstTime(&res, &sel)
+	}
+
+	*p = res
+	return p
+}
+
+// isEqualWord returns 1 if a equals b and 0 otherwise in constant time.
+func isEqualWord(a, b uint64) uint64 {
+	v := a ^ b
+	return 1 ^ (v|-v)>>63
+}
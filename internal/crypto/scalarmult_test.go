@@ -0,0 +1,96 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func randomScalarInt(t testing.TB) *big.Int {
+	t.Helper()
+
+	k, err := rand.Int(rand.Reader, secp256k1.params.N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// mulVarTime sets p = k*a using the binary expansion algorithm which scans k
+// from its most significant bit. It serves as a plain reference for the
+// optimized multiplications in tests only since its running time depends
+// on k.
+func (p *jacobianPoint) mulVarTime(a *jacobianPoint, k *scalarVal) *jacobianPoint {
+	base := *a
+	var res jacobianPoint
+	res.setInfinity()
+
+	started := false
+	for i := 255; i >= 0; i-- {
+		if started {
+			res.double(&res)
+		}
+		if k.bit(i) == 1 {
+			res.add(&res, &base)
+			started = true
+		}
+	}
+
+	*p = res
+	return p
+}
+
+func TestScalarMultConstTime(t *testing.T) {
+	t.Parallel()
+
+	n := secp256k1.params.N
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(15),
+		big.NewInt(16),
+		new(big.Int).Sub(n, big.NewInt(1)),
+		new(big.Int).Set(n),
+		new(big.Int).Add(n, big.NewInt(1)),
+		new(big.Int).Lsh(big.NewInt(1), 255),
+		new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)),
+		new(big.Int).Lsh(big.NewInt(3), 300),
+	}
+	for i := 0; i < 16; i++ {
+		scalars = append(scalars, randomScalarInt(t))
+	}
+
+	px, py := secp256k1.ScalarMult(secp256k1.params.Gx, secp256k1.params.Gy, randomScalarInt(t).Bytes())
+	points := [][2]*big.Int{
+		{secp256k1.params.Gx, secp256k1.params.Gy},
+		{px, py},
+	}
+
+	for _, pt := range points {
+		for _, k := range scalars {
+			wantX, wantY := secp256k1.ScalarMult(pt[0], pt[1], k.Bytes())
+			x, y := secp256k1.scalarMultConstTime(pt[0], pt[1], k.Bytes())
+			if !equalAffine(x, y, wantX, wantY) {
+				t.Fatalf("k=%x: (%x,%x) != (%x,%x)", k, x, y, wantX, wantY)
+			}
+		}
+	}
+
+	if x, y := secp256k1.scalarMultConstTime(nil, nil, []byte{7}); x != nil || y != nil {
+		t.Fatalf("(%x,%x) is not the point at infinity", x, y)
+	}
+}
+
+func equalAffine(x1, y1, x2, y2 *big.Int) bool {
+	if x1 == nil || x2 == nil {
+		return x1 == nil && x2 == nil
+	}
+	return x1.Cmp(x2) == 0 && y1.Cmp(y2) == 0
+}
+
+func BenchmarkScalarMultConstTime(b *testing.B) {
+	k := randomScalarInt(b).Bytes()
+	for i := 0; i < b.N; i++ {
+		secp256k1.scalarMultConstTime(secp256k1.params.Gx, secp256k1.params.Gy, k)
+	}
+}
@@ -0,0 +1,185 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/evercoinx/bitcoin/internal/hash"
+)
+
+const (
+	// XOnlyPublicKeySize is the size of a public key serialized as its x
+	// coordinate only as defined by BIP 340.
+	XOnlyPublicKeySize = 32
+
+	// SchnorrSignatureSize is the size of a serialized BIP 340 signature.
+	SchnorrSignatureSize = 64
+)
+
+const (
+	tagBIP340Aux       = "BIP0340/aux"
+	tagBIP340Nonce     = "BIP0340/nonce"
+	tagBIP340Challenge = "BIP0340/challenge"
+)
+
+// SchnorrSignature describes a BIP 340 signature (r,s) where r is the x
+// coordinate of the nonce point R with an even y and s is a scalar.
+type SchnorrSignature struct {
+	R, S *big.Int
+}
+
+// Serialize returns the signature as 64 bytes: r followed by s.
+func (sig *SchnorrSignature) Serialize() []byte {
+	b := make([]byte, SchnorrSignatureSize)
+	sig.R.FillBytes(b[:32])
+	sig.S.FillBytes(b[32:])
+	return b
+}
+
+// ParseSchnorrSignature parses a 64-byte BIP 340 signature and checks that
+// r is less than p and s is less than n.
+func ParseSchnorrSignature(b []byte) (*SchnorrSignature, error) {
+	if len(b) != SchnorrSignatureSize {
+		return nil, fmt.Errorf("schnorr: signature must be %d bytes, got %d", SchnorrSignatureSize, len(b))
+	}
+
+	params := secp256k1.Params()
+	r := new(big.Int).SetBytes(b[:32])
+	if r.Cmp(params.P) >= 0 {
+		return nil, errors.New("schnorr: r is not less than the field size")
+	}
+	s := new(big.Int).SetBytes(b[32:])
+	if s.Cmp(params.N) >= 0 {
+		return nil, errors.New("schnorr: s is not less than the curve order")
+	}
+	return &SchnorrSignature{R: r, S: s}, nil
+}
+
+// SerializeXOnly returns the x coordinate of the public key as 32 bytes.
+// The parity of y is lost, so the key is implicitly the one with an even y.
+func (k *PublicKey) SerializeXOnly() []byte {
+	return k.X.FillBytes(make([]byte, XOnlyPublicKeySize))
+}
+
+// ParseXOnlyPublicKey parses a 32-byte x-only public key and lifts it to
+// the point with an even y.
+func ParseXOnlyPublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != XOnlyPublicKeySize {
+		return nil, fmt.Errorf("schnorr: x-only public key must be %d bytes, got %d", XOnlyPublicKeySize, len(b))
+	}
+	return liftX(new(big.Int).SetBytes(b))
+}
+
+// liftX returns the point with the x coordinate and an even y.
+func liftX(x *big.Int) (*PublicKey, error) {
+	if x.Cmp(secp256k1.Params().P) >= 0 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	y, err := decompressY(x, false)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{X: new(big.Int).Set(x), Y: y}, nil
+}
+
+// SchnorrSign signs a message with the private key according to BIP 340.
+//
+// The auxiliary random data must be 32 bytes; it is mixed into the nonce
+// derivation to protect against side-channel attacks. Passing 32 zero bytes
+// makes signing fully deterministic.
+func SchnorrSign(key *PrivateKey, msg, auxRand []byte) (*SchnorrSignature, error) {
+	if key == nil || !isValidScalar(key.D) {
+		return nil, ErrInvalidPrivateKey
+	}
+	if len(auxRand) != 32 {
+		return nil, fmt.Errorf("schnorr: auxiliary random data must be 32 bytes, got %d", len(auxRand))
+	}
+
+	n := secp256k1.Params().N
+	d := evenYScalar(key.D, key.Y)
+	px := key.SerializeXOnly()
+
+	// t = bytes(d) xor hash_aux(a)
+	t := d.FillBytes(make([]byte, 32))
+	for i, b := range hash.TaggedHash(tagBIP340Aux, auxRand) {
+		t[i] ^= b
+	}
+
+	k := new(big.Int).SetBytes(hash.TaggedHash(tagBIP340Nonce, t, px, msg))
+	k.Mod(k, n)
+	if k.Sign() == 0 {
+		return nil, errors.New("schnorr: nonce is zero")
+	}
+
+	rx, ry := secp256k1.ScalarBaseMult(k.Bytes())
+	k = evenYScalar(k, ry)
+
+	// s = k + ed % n
+	var ks, ds, es, s scalarVal
+	ks.setBig(k)
+	ds.setBig(d)
+	es.setBig(schnorrChallenge(rx, key.X, msg))
+	s.mul(&es, &ds)
+	s.add(&s, &ks)
+
+	sig := &SchnorrSignature{R: rx, S: s.big()}
+	if !SchnorrVerify(key.Public(), msg, sig) {
+		return nil, errors.New("schnorr: produced signature is invalid")
+	}
+	return sig, nil
+}
+
+// SchnorrVerify reports whether the BIP 340 signature of the message is
+// valid for the public key. Only the x coordinate of the public key is used.
+func SchnorrVerify(pub *PublicKey, msg []byte, sig *SchnorrSignature) bool {
+	if !isValidSchnorrInput(pub, sig) {
+		return false
+	}
+
+	p, err := liftX(pub.X)
+	if err != nil {
+		return false
+	}
+
+	// R = sG - eP
+	e := schnorrChallenge(sig.R, p.X, msg)
+	e.Sub(secp256k1.params.N, e)
+	x1, y1 := secp256k1.ScalarBaseMult(sig.S.Bytes())
+	x2, y2 := secp256k1.ScalarMult(p.X, p.Y, e.Bytes())
+	rx, ry := secp256k1.Add(x1, y1, x2, y2)
+
+	return rx != nil && ry.Bit(0) == 0 && rx.Cmp(sig.R) == 0
+}
+
+// isValidSchnorrInput reports whether the public key and the signature are
+// set and r and s are in range.
+func isValidSchnorrInput(pub *PublicKey, sig *SchnorrSignature) bool {
+	if pub == nil || pub.X == nil || sig == nil || sig.R == nil || sig.S == nil {
+		return false
+	}
+
+	params := secp256k1.Params()
+	return sig.R.Sign() >= 0 && sig.R.Cmp(params.P) < 0 && sig.S.Sign() >= 0 && sig.S.Cmp(params.N) < 0
+}
+
+// schnorrChallenge returns the BIP 340 challenge
+// e = hash_challenge(bytes(rx) || bytes(px) || m) % n.
+func schnorrChallenge(rx, px *big.Int, msg []byte) *big.Int {
+	e := new(big.Int).SetBytes(hash.TaggedHash(
+		tagBIP340Challenge,
+		rx.FillBytes(make([]byte, 32)),
+		px.FillBytes(make([]byte, 32)),
+		msg,
+	))
+	return e.Mod(e, secp256k1.Params().N)
+}
+
+// evenYScalar returns k if the point k*G has an even y, otherwise n-k.
+func evenYScalar(k, y *big.Int) *big.Int {
+	if y.Bit(0) == 0 {
+		return new(big.Int).Set(k)
+	}
+	return new(big.Int).Sub(secp256k1.Params().N, k)
+}
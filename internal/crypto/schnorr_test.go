@@ -0,0 +1,129 @@
+package crypto_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+)
+
+func TestSchnorrBIP340Vectors(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("testdata/bip340_test_vectors.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rec := range records[1:] {
+		rec := rec
+		t.Run(rec[0], func(t *testing.T) {
+			pubKey := mustDecodeHex(t, rec[2])
+			msg := mustDecodeHex(t, rec[4])
+			sigBytes := mustDecodeHex(t, rec[5])
+			want := rec[6] == "TRUE"
+
+			if rec[1] != "" {
+				key, err := crypto.NewPrivateKey(mustDecodeHex(t, rec[1]))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := key.SerializeXOnly(); !bytes.Equal(got, pubKey) {
+					t.Fatalf("public key: %X != %X", got, pubKey)
+				}
+
+				sig, err := crypto.SchnorrSign(key, msg, mustDecodeHex(t, rec[3]))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := sig.Serialize(); !bytes.Equal(got, sigBytes) {
+					t.Fatalf("signature: %X != %X", got, sigBytes)
+				}
+			}
+
+			got := verifySchnorr(pubKey, msg, sigBytes)
+			if got != want {
+				t.Fatalf("%t != %t: %s", got, want, rec[7])
+			}
+		})
+	}
+}
+
+func verifySchnorr(pubKey, msg, sigBytes []byte) bool {
+	pub, err := crypto.ParseXOnlyPublicKey(pubKey)
+	if err != nil {
+		return false
+	}
+	sig, err := crypto.ParseSchnorrSignature(sigBytes)
+	if err != nil {
+		return false
+	}
+	return crypto.SchnorrVerify(pub, msg, sig)
+}
+
+func TestSchnorrSign(t *testing.T) {
+	t.Parallel()
+
+	// the public key 1485*G has an odd y, so the secret scalar gets negated
+	key, err := crypto.NewPrivateKeyFromInt(big.NewInt(1485))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("Hello, world!")
+	sig, err := crypto.SchnorrSign(key, msg, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !crypto.SchnorrVerify(key.Public(), msg, sig) {
+		t.Fatal("signature is not verified")
+	}
+	if crypto.SchnorrVerify(key.Public(), []byte("Hello, world?"), sig) {
+		t.Fatal("signature is verified for another message")
+	}
+
+	if _, err := crypto.SchnorrSign(key, msg, nil); err == nil {
+		t.Fatal("expected error for missing auxiliary data")
+	}
+}
+
+func TestParseXOnlyPublicKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.NewPrivateKeyFromInt(big.NewInt(1485))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := crypto.ParseXOnlyPublicKey(key.SerializeXOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.X.Cmp(key.X) != 0 || pub.Y.Bit(0) != 0 {
+		t.Fatalf("(%x,%x) is not the even point for x %x", pub.X, pub.Y, key.X)
+	}
+
+	if _, err := crypto.ParseXOnlyPublicKey(key.SerializeCompressed()); err == nil {
+		t.Fatal("expected error for compressed key")
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
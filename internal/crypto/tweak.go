@@ -0,0 +1,114 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TweakSize is the size of a tweak in bytes.
+const TweakSize = 32
+
+var (
+	ErrInvalidTweak  = errors.New("crypto: tweak is out of range")
+	ErrTweakInfinity = errors.New("crypto: tweaked key is the point at infinity")
+)
+
+// TweakAdd returns the private key d + t where t is a 32-byte tweak in
+// big-endian form, as used by BIP 32 derivation and Taproot output keys.
+//
+// It fails if the tweak is not less than n or the resulting key is zero.
+func (k *PrivateKey) TweakAdd(tweak []byte) (*PrivateKey, error) {
+	if k == nil || !isValidScalar(k.D) {
+		return nil, ErrInvalidPrivateKey
+	}
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+
+	var d scalarVal
+	d.setBig(k.D)
+	d.add(&d, t)
+	if d.isZero() == 1 {
+		return nil, ErrTweakInfinity
+	}
+	return NewPrivateKeyFromInt(d.big())
+}
+
+// TweakMul returns the private key d*t where t is a 32-byte tweak in
+// big-endian form. The tweak must be in the range [1, n-1].
+func (k *PrivateKey) TweakMul(tweak []byte) (*PrivateKey, error) {
+	if k == nil || !isValidScalar(k.D) {
+		return nil, ErrInvalidPrivateKey
+	}
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+	if t.isZero() == 1 {
+		return nil, ErrInvalidTweak
+	}
+
+	// the product of non-zero scalars modulo the prime n is never zero
+	var d scalarVal
+	d.setBig(k.D)
+	d.mul(&d, t)
+	return NewPrivateKeyFromInt(d.big())
+}
+
+// TweakAdd returns the public key P + t*G where t is a 32-byte tweak in
+// big-endian form. It matches TweakAdd of the corresponding private key.
+//
+// It fails if the tweak is not less than n or the resulting point is at
+// infinity.
+func (k *PublicKey) TweakAdd(tweak []byte) (*PublicKey, error) {
+	if k == nil || !k.isValid() {
+		return nil, ErrInvalidPublicKey
+	}
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+
+	tb := t.bytes()
+	tx, ty := secp256k1.ScalarBaseMult(tb[:])
+	x, y := secp256k1.Add(k.X, k.Y, tx, ty)
+	if x == nil {
+		return nil, ErrTweakInfinity
+	}
+	return &PublicKey{X: x, Y: y}, nil
+}
+
+// TweakMul returns the public key t*P where t is a 32-byte tweak in
+// big-endian form. The tweak must be in the range [1, n-1].
+func (k *PublicKey) TweakMul(tweak []byte) (*PublicKey, error) {
+	if k == nil || !k.isValid() {
+		return nil, ErrInvalidPublicKey
+	}
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+	if t.isZero() == 1 {
+		return nil, ErrInvalidTweak
+	}
+
+	tb := t.bytes()
+	x, y := secp256k1.ScalarMult(k.X, k.Y, tb[:])
+	return &PublicKey{X: x, Y: y}, nil
+}
+
+// parseTweak parses a 32-byte tweak which must be less than n.
+func parseTweak(b []byte) (*scalarVal, error) {
+	if len(b) != TweakSize {
+		return nil, fmt.Errorf("crypto: tweak must be %d bytes, got %d", TweakSize, len(b))
+	}
+
+	var buf [32]byte
+	copy(buf[:], b)
+	var t scalarVal
+	if _, overflow := t.setBytes(&buf); overflow {
+		return nil, ErrInvalidTweak
+	}
+	return &t, nil
+}
@@ -0,0 +1,138 @@
+package crypto_test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+)
+
+func TestTweak(t *testing.T) {
+	t.Parallel()
+
+	const (
+		key    = "f8b8af8ce3c7cca5e300d33939540c10d45ce001b8f252bfbc57ba0342904181"
+		tweak  = "e9873d79c6d87dc0fb6a5778633389f4453213303da61f20bd67fc233aa33262"
+		order  = "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"
+		zero   = "0000000000000000000000000000000000000000000000000000000000000000"
+		negKey = "074750731c38335a1cff2cc6c6abf3ede651fce4f6564d7c037aa4898da5ffc0"
+	)
+
+	tests := []struct {
+		name       string
+		tweak      string
+		mul        bool
+		wantKey    string
+		wantPubKey string
+		wantErr    error
+	}{
+		{
+			name:       "add",
+			tweak:      tweak,
+			wantKey:    "e23fed06aaa04a66de6b2ab19c8796065ee0164b474fd1a4b9ed5799acfd32a2",
+			wantPubKey: "022cde8e4db9300d10c868f27231233404eb634f45e4af5ec18c8c48eb4c54c9e4",
+		},
+		{
+			name:       "mul",
+			tweak:      tweak,
+			mul:        true,
+			wantKey:    "df2aa2d20eec282551f4a953c84e5518b7bb86ff7b6e15e2daa7a69427d55c92",
+			wantPubKey: "02e7dc161634ed795eaa6fbce0e07401b7b57c80afee6b340099e7d06e3eed036b",
+		},
+		{
+			name:       "add zero",
+			tweak:      zero,
+			wantKey:    key,
+			wantPubKey: "0292df7b245b81aa637ab4e867c8d511008f79161a97d64f2ac709600352f7acbc",
+		},
+		{
+			name:    "add overflow",
+			tweak:   order,
+			wantErr: crypto.ErrInvalidTweak,
+		},
+		{
+			name:    "add negated key",
+			tweak:   negKey,
+			wantErr: crypto.ErrTweakInfinity,
+		},
+		{
+			name:    "mul zero",
+			tweak:   zero,
+			mul:     true,
+			wantErr: crypto.ErrInvalidTweak,
+		},
+		{
+			name:    "mul overflow",
+			tweak:   order,
+			mul:     true,
+			wantErr: crypto.ErrInvalidTweak,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			priv, err := crypto.NewPrivateKey(mustDecodeHex(t, key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			pub := priv.Public()
+			tweak := mustDecodeHex(t, tt.tweak)
+
+			var gotPriv *crypto.PrivateKey
+			var gotPub *crypto.PublicKey
+			var errPriv, errPub error
+			if tt.mul {
+				gotPriv, errPriv = priv.TweakMul(tweak)
+				gotPub, errPub = pub.TweakMul(tweak)
+			} else {
+				gotPriv, errPriv = priv.TweakAdd(tweak)
+				gotPub, errPub = pub.TweakAdd(tweak)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(errPriv, tt.wantErr) || !errors.Is(errPub, tt.wantErr) {
+					t.Fatalf("%v, %v != %v", errPriv, errPub, tt.wantErr)
+				}
+				return
+			}
+			if errPriv != nil {
+				t.Fatal(errPriv)
+			}
+			if errPub != nil {
+				t.Fatal(errPub)
+			}
+
+			if got := gotPriv.Bytes(); !bytes.Equal(got, mustDecodeHex(t, tt.wantKey)) {
+				t.Fatalf("private key: %x != %s", got, tt.wantKey)
+			}
+			if got := gotPub.SerializeCompressed(); !bytes.Equal(got, mustDecodeHex(t, tt.wantPubKey)) {
+				t.Fatalf("public key: %x != %s", got, tt.wantPubKey)
+			}
+			if !gotPriv.Public().IsEqual(gotPub) {
+				t.Fatal("tweaked public key does not match tweaked private key")
+			}
+		})
+	}
+}
+
+func TestTweakInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.NewPrivateKeyFromInt(big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := priv.TweakAdd(make([]byte, 31)); err == nil {
+		t.Fatal("expected error for short tweak")
+	}
+
+	offCurve := &crypto.PublicKey{X: big.NewInt(1), Y: big.NewInt(1)}
+	if _, err := offCurve.TweakAdd(make([]byte, crypto.TweakSize)); !errors.Is(err, crypto.ErrInvalidPublicKey) {
+		t.Fatalf("%v != %v", err, crypto.ErrInvalidPublicKey)
+	}
+}
@@ -3,6 +3,7 @@ package encoding
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 
@@ -24,8 +25,8 @@ func (v AddressVersion) String() string {
 const (
 	base58Symbols = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
 
-	addressSize  = 25 // in bytes; 1B is a version, 20B is a payload, 4B is a checksum
-	checksumSize = 4  // in bytes
+	addressPayloadSize = 20 // in bytes; a hash of a public key or a script
+	checksumSize       = 4  // in bytes
 )
 
 var (
@@ -33,6 +34,17 @@ var (
 	base58SymbolToIndex = make(map[rune]int, len(base58Symbols))
 )
 
+// InvalidBase58CharError describes a character outside of the Base58
+// alphabet found at a byte position of a decoded string.
+type InvalidBase58CharError struct {
+	Char rune
+	Pos  int
+}
+
+func (e *InvalidBase58CharError) Error() string {
+	return fmt.Sprintf("base58: invalid character %q at position %d", e.Char, e.Pos)
+}
+
 func init() {
 	for i, a := range base58Symbols {
 		base58SymbolToIndex[a] = i
@@ -41,26 +53,29 @@ func init() {
 
 // Base58CheckEncode encodes a byte slice into a Bitcoin address.
 func Base58CheckEncode(payload []byte, version AddressVersion) string {
-	ver := []byte{byte(version)}
-	verPayload := bytes.Join([][]byte{ver, payload}, nil)
+	return Base58CheckEncodeVersion([]byte{byte(version)}, payload)
+}
 
-	csum := hash.Hash256(verPayload)[:checksumSize]
-	bs := bytes.Join([][]byte{verPayload, csum}, nil)
+// Base58CheckEncodeVersion encodes a payload of any size prefixed with a
+// version of any size, e.g. the 4-byte version of an extended key.
+func Base58CheckEncodeVersion(version, payload []byte) string {
+	data := bytes.Join([][]byte{version, payload}, nil)
+	csum := hash.Hash256(data)[:checksumSize]
+	return Base58Encode(bytes.Join([][]byte{data, csum}, nil))
+}
 
+// Base58Encode encodes a byte slice into a Base58 string. Every leading zero
+// byte is encoded as '1'.
+func Base58Encode(bs []byte) string {
 	var cnt int
 	for _, b := range bs {
-		if b != byte(AddressVersionPublicKeyHash) {
+		if b != 0 {
 			break
 		}
 		cnt++
 	}
 
-	encoded := base58Encode(bs[cnt:])
-	return strings.Repeat("1", cnt) + reverseStr(encoded)
-}
-
-func base58Encode(bs []byte) string {
-	num := encoding.BytesToInt(bs, encoding.BigEndian)
+	num := encoding.BytesToInt(bs[cnt:], encoding.BigEndian)
 	mod := new(big.Int)
 	var out strings.Builder
 
@@ -69,7 +84,7 @@ func base58Encode(bs []byte) string {
 		sym := base58Symbols[mod.Int64()]
 		out.WriteByte(sym)
 	}
-	return out.String()
+	return strings.Repeat("1", cnt) + reverseStr(out.String())
 }
 
 func reverseStr(str string) string {
@@ -82,36 +97,70 @@ func reverseStr(str string) string {
 
 // Base58CheckDecode decodes a Bitcoin address into a byte slice.
 func Base58CheckDecode(addr string) ([]byte, error) {
-	decoded, err := base58Decode(addr)
+	_, payload, err := Base58CheckDecodeVersion(addr, 1)
 	if err != nil {
 		return nil, err
 	}
+	if len(payload) != addressPayloadSize {
+		return nil, fmt.Errorf("base58check: address payload must be %d bytes, got %d", addressPayloadSize, len(payload))
+	}
+	return payload, nil
+}
+
+// Base58CheckDecodeVersion decodes a Base58Check string and splits the data
+// into a version of the given size and a payload of any size.
+func Base58CheckDecodeVersion(s string, versionSize int) (version, payload []byte, err error) {
+	decoded, err := Base58Decode(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := verifyChecksum(decoded)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) < versionSize {
+		return nil, nil, fmt.Errorf("base58check: data is shorter than %d-byte version", versionSize)
+	}
+	return data[:versionSize], data[versionSize:], nil
+}
+
+// verifyChecksum checks the trailing checksum of decoded Base58Check data
+// and returns the data without it.
+func verifyChecksum(decoded []byte) ([]byte, error) {
+	if len(decoded) < checksumSize {
+		return nil, errors.New("base58check: data is too short")
+	}
 
 	csumStartIdx := len(decoded) - checksumSize
-	verPayload := decoded[:csumStartIdx]
+	data := decoded[:csumStartIdx]
 
 	expCsum := decoded[csumStartIdx:]
-	actCsum := hash.Hash256(verPayload)[:checksumSize]
+	actCsum := hash.Hash256(data)[:checksumSize]
 	if !bytes.Equal(expCsum, actCsum) {
 		return nil, errors.New("base58check: bad checksum")
 	}
-
-	return decoded[1:csumStartIdx], nil
+	return data, nil
 }
 
-func base58Decode(addr string) ([]byte, error) {
-	num := new(big.Int)
+// Base58Decode decodes a Base58 string into a byte slice. Every leading '1'
+// is decoded as a zero byte. A character outside of the alphabet is reported
+// with an *InvalidBase58CharError.
+func Base58Decode(s string) ([]byte, error) {
+	var cnt int
+	for cnt < len(s) && s[cnt] == base58Symbols[0] {
+		cnt++
+	}
 
-	for _, a := range addr {
-		num.Mul(num, num58)
-		if idx, ok := base58SymbolToIndex[a]; ok {
-			num.Add(num, big.NewInt(int64(idx)))
+	num := new(big.Int)
+	digit := new(big.Int)
+	for i, a := range s[cnt:] {
+		idx, ok := base58SymbolToIndex[a]
+		if !ok {
+			return nil, &InvalidBase58CharError{Char: a, Pos: cnt + i}
 		}
+		num.Mul(num, num58)
+		num.Add(num, digit.SetInt64(int64(idx)))
 	}
 
-	bs, err := encoding.IntToBytes(num, addressSize, encoding.BigEndian)
-	if err != nil {
-		return nil, err
-	}
-	return bs, nil
+	return append(make([]byte, cnt), num.Bytes()...), nil
 }
@@ -3,6 +3,7 @@ package encoding
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"testing"
 )
 
@@ -87,3 +88,271 @@ func TestBase58CheckDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestBase58CheckEncodeVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version string
+		payload string
+		want    string
+	}{
+		{
+			"extended public key",
+			"0488b21e",
+			"000000000000000000873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d5080339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c2",
+			"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		},
+		{
+			"wif",
+			"80",
+			"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+			"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ",
+		},
+		{
+			"leading zero bytes",
+			"00",
+			"0000000000000000000000000000000000000000",
+			"1111111111111111111114oLvT2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := hex.DecodeString(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			payload, err := hex.DecodeString(tt.payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := Base58CheckEncodeVersion(version, payload)
+			if got != tt.want {
+				t.Fatalf("%s != %s", got, tt.want)
+			}
+
+			gotVersion, gotPayload, err := Base58CheckDecodeVersion(got, len(version))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(gotVersion, version) {
+				t.Fatalf("%x != %x", gotVersion, version)
+			}
+			if !bytes.Equal(gotPayload, payload) {
+				t.Fatalf("%x != %x", gotPayload, payload)
+			}
+		})
+	}
+}
+
+func TestBase58CheckDecodeVersionInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		encoded     string
+		versionSize int
+	}{
+		{
+			"bad checksum",
+			"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet9",
+			4,
+		},
+		{
+			"too short",
+			"1",
+			0,
+		},
+		{
+			"version is longer than data",
+			"1111111111111111111114oLvT2",
+			22,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Base58CheckDecodeVersion(tt.encoded, tt.versionSize); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+// Test vectors are taken from Bitcoin Core's src/test/data/base58_encode_decode.json.
+var base58TestVectors = []struct {
+	hex     string
+	encoded string
+}{
+	{
+		"",
+		"",
+	},
+	{
+		"61",
+		"2g",
+	},
+	{
+		"626262",
+		"a3gV",
+	},
+	{
+		"636363",
+		"aPEr",
+	},
+	{
+		"73696d706c792061206c6f6e6720737472696e67",
+		"2cFupjhnEsSn59qHXstmK2ffpLv2",
+	},
+	{
+		"00eb15231dfceb60925886b67d065299925915aeb172c06647",
+		"1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L",
+	},
+	{
+		"516b6fcd0f",
+		"ABnLTmg",
+	},
+	{
+		"bf4f89001e670274dd",
+		"3SEo3LWLoPntC",
+	},
+	{
+		"572e4794",
+		"3EFU7m",
+	},
+	{
+		"ecac89cad93923c02321",
+		"EJDM8drfXA6uyA",
+	},
+	{
+		"10c8511e",
+		"Rt5zm",
+	},
+	{
+		"00000000000000000000",
+		"1111111111",
+	},
+	{
+		"000111d38e5fc9071ffcd20b4a763cc9ae4f252bb4e48fd66a835e252ada93ff480d6dd43dc62a641155a5",
+		"123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz",
+	},
+	{
+		"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f606162636465666768696a6b6c6d6e6f707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9fa0a1a2a3a4a5a6a7a8a9aaabacadaeafb0b1b2b3b4b5b6b7b8b9babbbcbdbebfc0c1c2c3c4c5c6c7c8c9cacbcccdcecfd0d1d2d3d4d5d6d7d8d9dadbdcdddedfe0e1e2e3e4e5e6e7e8e9eaebecedeeeff0f1f2f3f4f5f6f7f8f9fafbfcfdfeff",
+		"1cWB5HCBdLjAuqGGReWE3R3CguuwSjw6RHn39s2yuDRTS5NsBgNiFpWgAnEx6VQi8csexkgYw3mdYrMHr8x9i7aEwP8kZ7vccXWqKDvGv3u1GxFKPuAkn8JCPPGDMf3vMMnbzm6Nh9zh1gcNsMvH3ZNLmP5fSG6DGbbi2tuwMWPthr4boWwCxf7ewSgNQeacyozhKDDQQ1qL5fQFUW52QKUZDZ5fw3KXNQJMcNTcaB723LchjeKun7MuGW5qyCBZYzA1KjofN1gYBV3NqyhQJ3Ns746GNuf9N2pQPmHz4xpnSrrfCvy6TVVz5d4PdrjeshsWQwpZsZGzvbdAdN8MKV5QsBDY",
+	},
+}
+
+func TestBase58Encode(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range base58TestVectors {
+		t.Run(tt.encoded, func(t *testing.T) {
+			bs, err := hex.DecodeString(tt.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := Base58Encode(bs)
+			if got != tt.encoded {
+				t.Fatalf("%s != %s", got, tt.encoded)
+			}
+		})
+	}
+}
+
+func TestBase58Decode(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range base58TestVectors {
+		t.Run(tt.encoded, func(t *testing.T) {
+			want, err := hex.DecodeString(tt.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Base58Decode(tt.encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("%x != %x", got, want)
+			}
+		})
+	}
+}
+
+func TestBase58DecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		encoded string
+		char    rune
+		pos     int
+	}{
+		{
+			"lowercase l",
+			"invalid",
+			'l',
+			4,
+		},
+		{
+			"zero",
+			"1110abc",
+			'0',
+			3,
+		},
+		{
+			"uppercase o",
+			"O",
+			'O',
+			0,
+		},
+		{
+			"uppercase i",
+			"3EFU7mI",
+			'I',
+			6,
+		},
+		{
+			"nul byte",
+			"a3gV\x00",
+			0,
+			4,
+		},
+		{
+			"whitespace",
+			" a3gV",
+			' ',
+			0,
+		},
+		{
+			"multi-byte character",
+			"2g\u00e9",
+			'\u00e9',
+			2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Base58Decode(tt.encoded)
+
+			var charErr *InvalidBase58CharError
+			if !errors.As(err, &charErr) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if charErr.Char != tt.char || charErr.Pos != tt.pos {
+				t.Fatalf("%q at %d != %q at %d", charErr.Char, charErr.Pos, tt.char, tt.pos)
+			}
+		})
+	}
+
+	var charErr *InvalidBase58CharError
+	if _, err := Base58CheckDecode("19g6oo8foQF5jfqK9gH2bLkFNwgCenRBP0"); !errors.As(err, &charErr) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
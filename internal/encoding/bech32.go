@@ -0,0 +1,225 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Bech32Encoding is a checksum variant of the Bech32 format.
+type Bech32Encoding int
+
+const (
+	// EncodingBech32 is the original variant of BIP 173.
+	EncodingBech32 Bech32Encoding = iota + 1
+	// EncodingBech32m is the variant of BIP 350 with a modified checksum
+	// constant.
+	EncodingBech32m
+)
+
+func (e Bech32Encoding) String() string {
+	switch e {
+	case EncodingBech32:
+		return "bech32"
+	case EncodingBech32m:
+		return "bech32m"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(e))
+	}
+}
+
+const (
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	bech32Separator    = '1'
+	bech32ChecksumSize = 6  // in characters
+	bech32MaxSize      = 90 // in characters
+
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+var (
+	bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	bech32CharToIndex [128]int8
+)
+
+func init() {
+	for i := range bech32CharToIndex {
+		bech32CharToIndex[i] = -1
+	}
+	for i, c := range bech32Charset {
+		bech32CharToIndex[c] = int8(i)
+	}
+}
+
+// Bech32Encode encodes 5-bit values of the data part with the human-readable
+// part into a Bech32 string with the checksum of the given variant.
+func Bech32Encode(hrp string, data []byte, enc Bech32Encoding) (string, error) {
+	if err := validateBech32HRP(hrp); err != nil {
+		return "", err
+	}
+	if len(hrp)+1+len(data)+bech32ChecksumSize > bech32MaxSize {
+		return "", fmt.Errorf("bech32: string exceeds %d characters", bech32MaxSize)
+	}
+	for _, d := range data {
+		if d > 31 {
+			return "", fmt.Errorf("bech32: data value %d exceeds 5 bits", d)
+		}
+	}
+
+	c, err := bech32EncodingConst(enc)
+	if err != nil {
+		return "", err
+	}
+
+	hrp = strings.ToLower(hrp)
+	values := append(bech32ExpandHRP(hrp), data...)
+	values = append(values, make([]byte, bech32ChecksumSize)...)
+	polymod := bech32Polymod(values) ^ c
+
+	var out strings.Builder
+	out.WriteString(hrp)
+	out.WriteByte(bech32Separator)
+	for _, d := range data {
+		out.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < bech32ChecksumSize; i++ {
+		out.WriteByte(bech32Charset[(polymod>>(5*(5-i)))&31])
+	}
+	return out.String(), nil
+}
+
+// Bech32Decode decodes a Bech32 or Bech32m string into the lowercase
+// human-readable part and 5-bit values of the data part without the
+// checksum. It also reports the variant of the checksum.
+func Bech32Decode(s string) (hrp string, data []byte, enc Bech32Encoding, err error) {
+	if len(s) > bech32MaxSize {
+		return "", nil, 0, fmt.Errorf("bech32: string exceeds %d characters", bech32MaxSize)
+	}
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, errors.New("bech32: string has mixed case")
+	}
+	s = strings.ToLower(s)
+
+	sepIdx := strings.LastIndexByte(s, bech32Separator)
+	if sepIdx < 1 {
+		return "", nil, 0, errors.New("bech32: human-readable part is missing")
+	}
+	if len(s)-sepIdx-1 < bech32ChecksumSize {
+		return "", nil, 0, errors.New("bech32: checksum is too short")
+	}
+
+	hrp = s[:sepIdx]
+	if err := validateBech32HRP(hrp); err != nil {
+		return "", nil, 0, err
+	}
+
+	values := make([]byte, 0, len(s)-sepIdx-1)
+	for i := sepIdx + 1; i < len(s); i++ {
+		c := s[i]
+		if c >= 128 || bech32CharToIndex[c] < 0 {
+			return "", nil, 0, fmt.Errorf("bech32: invalid character %q at position %d", c, i)
+		}
+		values = append(values, byte(bech32CharToIndex[c]))
+	}
+
+	switch bech32Polymod(append(bech32ExpandHRP(hrp), values...)) {
+	case bech32Const:
+		enc = EncodingBech32
+	case bech32mConst:
+		enc = EncodingBech32m
+	default:
+		return "", nil, 0, errors.New("bech32: bad checksum")
+	}
+	return hrp, values[:len(values)-bech32ChecksumSize], enc, nil
+}
+
+func validateBech32HRP(hrp string) error {
+	if len(hrp) == 0 || len(hrp) > bech32MaxSize-1-bech32ChecksumSize {
+		return fmt.Errorf("bech32: human-readable part must be 1 to %d characters, got %d",
+			bech32MaxSize-1-bech32ChecksumSize, len(hrp))
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return fmt.Errorf("bech32: invalid character %q at position %d", hrp[i], i)
+		}
+	}
+	return nil
+}
+
+func bech32EncodingConst(enc Bech32Encoding) (uint32, error) {
+	switch enc {
+	case EncodingBech32:
+		return bech32Const, nil
+	case EncodingBech32m:
+		return bech32mConst, nil
+	default:
+		return 0, fmt.Errorf("bech32: unknown encoding %s", enc)
+	}
+}
+
+// bech32ExpandHRP expands the human-readable part into values for the
+// checksum computation: the high bits of every character, a zero and the
+// low bits of every character.
+func bech32ExpandHRP(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// bech32Polymod computes the BCH checksum of the values as a remainder of
+// the polynomial division over GF(32).
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i, g := range bech32Generator {
+			if (top>>i)&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	return chk
+}
+
+// convertBits regroups a slice of fromBits-bit values into toBits-bit
+// values. With pad the trailing bits are padded with zeros, otherwise they
+// must be fewer than fromBits and all zeros.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxVal := uint32(1)<<toBits - 1
+	out := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+
+	for _, d := range data {
+		if d>>fromBits != 0 {
+			return nil, fmt.Errorf("bech32: value %d exceeds %d bits", d, fromBits)
+		}
+		acc = acc<<fromBits | uint32(d)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxVal))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxVal))
+		}
+	} else if bits >= fromBits {
+		return nil, errors.New("bech32: excess padding")
+	} else if acc<<(toBits-bits)&maxVal != 0 {
+		return nil, errors.New("bech32: non-zero padding")
+	}
+	return out, nil
+}
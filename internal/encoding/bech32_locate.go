@@ -0,0 +1,199 @@
+package encoding
+
+import (
+	"sort"
+	"strings"
+)
+
+// The Bech32 checksum is a BCH code over GF(32) whose generator has the
+// roots e^997, e^998 and e^999 in the extension field GF(1024). The syndromes
+// of a residue, i.e. its values at these roots, reveal the positions of up to
+// two substituted characters, which is what Bitcoin Core's LocateErrors does.
+//
+// GF(32) is GF(2)[x]/(x^5 + x^3 + 1), the field of the Bech32 checksum.
+// GF(1024) is GF(32)[v]/(v^2 + v + 1), whose element a*v + b is stored as
+// a<<5 | b so that GF(32) is embedded as the elements below 32. The element
+// e = 9*v + 23 is primitive.
+const (
+	gf32Modulus  = 0x29 // x^5 + x^3 + 1
+	gf1024Order  = 1023 // of the multiplicative group
+	gf1024Alpha  = 9<<5 | 23
+	gf32Subgroup = 33 // nonzero elements of GF(32) are the powers of e^33
+
+	bech32SyndromeRoot = 997
+)
+
+var (
+	gf1024Exp [gf1024Order]int
+	gf1024Log [gf1024Order + 1]int
+)
+
+func init() {
+	x := 1
+	for i := range gf1024Exp {
+		gf1024Exp[i] = x
+		x = gf1024Mul(x, gf1024Alpha)
+	}
+
+	gf1024Log[0] = -1
+	for i, x := range gf1024Exp {
+		gf1024Log[x] = i
+	}
+}
+
+func gf32Mul(a, b int) int {
+	var r int
+	for i := 0; i < 5; i++ {
+		if (b>>i)&1 == 1 {
+			r ^= a << i
+		}
+	}
+	for i := 8; i >= 5; i-- {
+		if (r>>i)&1 == 1 {
+			r ^= gf32Modulus << (i - 5)
+		}
+	}
+	return r
+}
+
+// gf1024Mul multiplies a*v + b by c*v + d, where v^2 = v + 1.
+func gf1024Mul(x, y int) int {
+	a, b := x>>5, x&31
+	c, d := y>>5, y&31
+
+	ac := gf32Mul(a, c)
+	hi := gf32Mul(a, d) ^ gf32Mul(b, c) ^ ac
+	lo := gf32Mul(b, d) ^ ac
+	return hi<<5 | lo
+}
+
+// bech32Syndromes evaluates the residue of the checksum, a polynomial of
+// degree 5 over GF(32), at e^997, e^998 and e^999.
+func bech32Syndromes(residue uint32) [3]int {
+	var syn [3]int
+	for k := range syn {
+		for j := 0; j < bech32ChecksumSize; j++ {
+			c := int(residue>>(5*j)) & 31
+			if c != 0 {
+				syn[k] ^= gf1024Exp[(gf1024Log[c]+(bech32SyndromeRoot+k)*j)%gf1024Order]
+			}
+		}
+	}
+	return syn
+}
+
+// Bech32LocateErrors returns the positions of up to two substituted
+// characters in a Bech32 or Bech32m string with a bad checksum, trying both
+// variants and preferring the one that explains the string with fewer
+// substitutions. It returns nil when the string has a valid checksum, is
+// malformed or has more errors than can be located.
+func Bech32LocateErrors(s string) []int {
+	if len(s) > bech32MaxSize || (strings.ToLower(s) != s && strings.ToUpper(s) != s) {
+		return nil
+	}
+	s = strings.ToLower(s)
+
+	sepIdx := strings.LastIndexByte(s, bech32Separator)
+	if sepIdx < 1 || len(s)-sepIdx-1 < bech32ChecksumSize {
+		return nil
+	}
+	hrp := s[:sepIdx]
+	if validateBech32HRP(hrp) != nil {
+		return nil
+	}
+
+	values := make([]byte, 0, len(s)-sepIdx-1)
+	for i := sepIdx + 1; i < len(s); i++ {
+		c := s[i]
+		if c >= 128 || bech32CharToIndex[c] < 0 {
+			return nil
+		}
+		values = append(values, byte(bech32CharToIndex[c]))
+	}
+	polymod := bech32Polymod(append(bech32ExpandHRP(hrp), values...))
+
+	var locations []int
+	for _, c := range []uint32{bech32Const, bech32mConst} {
+		residue := polymod ^ c
+		if residue == 0 {
+			return nil
+		}
+
+		possible := bech32LocateErrors(residue, len(values))
+		if len(locations) == 0 || (len(possible) > 0 && len(possible) < len(locations)) {
+			locations = possible
+		}
+	}
+
+	// positions are counted from the end of the string
+	for i, p := range locations {
+		locations[i] = len(s) - p - 1
+	}
+	sort.Ints(locations)
+	return locations
+}
+
+// bech32LocateErrors returns the positions of one or two errors counted from
+// the end of the data part of the given length which give the residue.
+func bech32LocateErrors(residue uint32, length int) []int {
+	syn := bech32Syndromes(residue)
+	s0, s1, s2 := syn[0], syn[1], syn[2]
+	l0, l1, l2 := gf1024Log[s0], gf1024Log[s1], gf1024Log[s2]
+
+	// a single error e1*x^p1 gives s1/s0 = s2/s1 = e^p1
+	if l0 != -1 && l1 != -1 && l2 != -1 && (2*l1-l2-l0+2*gf1024Order)%gf1024Order == 0 {
+		p1 := (l1 - l0 + gf1024Order) % gf1024Order
+		// e1 = s0 / e^(997*p1) must lie in GF(32)
+		le1 := l0 + (gf1024Order-bech32SyndromeRoot)*p1
+		if p1 < length && le1%gf32Subgroup == 0 {
+			return []int{p1}
+		}
+		return nil
+	}
+
+	// two errors e1*x^p1 + e2*x^p2: guess p1 and solve for the rest
+	for p1 := 0; p1 < length; p1++ {
+		// s2 + s1*e^p1 = e2*e^(998*p2)*(e^p2 + e^p1)
+		s2s1p1 := s2 ^ gf1024MulExp(s1, l1, p1)
+		if s2s1p1 == 0 {
+			continue
+		}
+		// s1 + s0*e^p1 = e2*e^(997*p2)*(e^p2 + e^p1)
+		s1s0p1 := s1 ^ gf1024MulExp(s0, l0, p1)
+		if s1s0p1 == 0 {
+			continue
+		}
+
+		p2 := (gf1024Log[s2s1p1] - gf1024Log[s1s0p1] + gf1024Order) % gf1024Order
+		if p2 >= length || p1 == p2 {
+			continue
+		}
+
+		// s1 + s0*e^p2 = e1*e^(997*p1)*(e^p1 + e^p2)
+		s1s0p2 := s1 ^ gf1024MulExp(s0, l0, p2)
+		if s1s0p2 == 0 {
+			continue
+		}
+
+		// both e1 and e2 must lie in GF(32)
+		invP1P2 := gf1024Order - gf1024Log[gf1024Exp[p1]^gf1024Exp[p2]]
+		le2 := gf1024Log[s1s0p1] + invP1P2 + (gf1024Order-bech32SyndromeRoot)*p2
+		if le2%gf32Subgroup != 0 {
+			continue
+		}
+		le1 := gf1024Log[s1s0p2] + invP1P2 + (gf1024Order-bech32SyndromeRoot)*p1
+		if le1%gf32Subgroup != 0 {
+			continue
+		}
+		return []int{p1, p2}
+	}
+	return nil
+}
+
+// gf1024MulExp multiplies x with the logarithm lx by e^p.
+func gf1024MulExp(x, lx, p int) int {
+	if x == 0 {
+		return 0
+	}
+	return gf1024Exp[(lx+p)%gf1024Order]
+}
@@ -0,0 +1,98 @@
+package encoding
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBech32LocateErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    string
+		want []int
+	}{
+		{
+			"valid bech32",
+			"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+			nil,
+		},
+		{
+			"valid bech32m",
+			"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+			nil,
+		},
+		{
+			"bech32 single error",
+			"abcdef1qpzrz9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+			[]int{11},
+		},
+		{
+			"bech32 two errors",
+			"test1zg69w7y6hn0aqy352euf40x77qddq3dc",
+			[]int{9, 16},
+		},
+		{
+			"bech32m single error",
+			"abcdef1l7aum6echk45nj3r0wdvt2fg8x9yrzpqzd3ryx",
+			[]int{22},
+		},
+		{
+			"bech32m two errors",
+			"test1zg69v7y60n00qy352euf40x77qcusag6",
+			[]int{13, 32},
+		},
+		{
+			"error in checksum",
+			"a12uel5m",
+			[]int{7},
+		},
+		{
+			"uppercase segwit address",
+			"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T5",
+			[]int{41},
+		},
+		{
+			"checksum calculated with uppercase human-readable part",
+			"A1G7SGD8",
+			nil,
+		},
+		{
+			"invalid character",
+			"x1b4n0q5v",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Bech32LocateErrors(tt.s)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("%v != %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBech32LocateErrorsSingleSubstitution(t *testing.T) {
+	t.Parallel()
+
+	for _, addr := range []string{
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+	} {
+		for p := 3; p < len(addr); p++ {
+			for i := 0; i < len(bech32Charset); i++ {
+				if bech32Charset[i] == addr[p] {
+					continue
+				}
+				s := addr[:p] + bech32Charset[i:i+1] + addr[p+1:]
+
+				if got := Bech32LocateErrors(s); !reflect.DeepEqual(got, []int{p}) {
+					t.Fatalf("%s: %v != [%d]", s, got, p)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package encoding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBech32Decode(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors are taken from BIP 173 and BIP 350.
+	tests := []struct {
+		name string
+		s    string
+		enc  Bech32Encoding
+	}{
+		{
+			"bech32 uppercase",
+			"A12UEL5L",
+			EncodingBech32,
+		},
+		{
+			"bech32 lowercase",
+			"a12uel5l",
+			EncodingBech32,
+		},
+		{
+			"bech32 long human-readable part",
+			"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+			EncodingBech32,
+		},
+		{
+			"bech32 full charset",
+			"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+			EncodingBech32,
+		},
+		{
+			"bech32 separator in human-readable part",
+			"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+			EncodingBech32,
+		},
+		{
+			"bech32 punctuation human-readable part",
+			"?1ezyfcl",
+			EncodingBech32,
+		},
+		{
+			"bech32m uppercase",
+			"A1LQFN3A",
+			EncodingBech32m,
+		},
+		{
+			"bech32m lowercase",
+			"a1lqfn3a",
+			EncodingBech32m,
+		},
+		{
+			"bech32m long human-readable part",
+			"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6",
+			EncodingBech32m,
+		},
+		{
+			"bech32m full charset",
+			"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+			EncodingBech32m,
+		},
+		{
+			"bech32m separator in human-readable part",
+			"split1checkupstagehandshakeupstreamerranterredcaperredlc445v",
+			EncodingBech32m,
+		},
+		{
+			"bech32m punctuation human-readable part",
+			"?1v759aa",
+			EncodingBech32m,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hrp, data, enc, err := Bech32Decode(tt.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if enc != tt.enc {
+				t.Fatalf("%s != %s", enc, tt.enc)
+			}
+
+			got, err := Bech32Encode(hrp, data, enc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := strings.ToLower(tt.s); got != want {
+				t.Fatalf("%s != %s", got, want)
+			}
+		})
+	}
+}
+
+func TestBech32DecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    string
+	}{
+		{
+			"human-readable part character out of range",
+			"\x201nwldj5",
+		},
+		{
+			"overall max length exceeded",
+			"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx",
+		},
+		{
+			"no separator",
+			"pzry9x0s0muk",
+		},
+		{
+			"empty human-readable part",
+			"1pzry9x0s0muk",
+		},
+		{
+			"invalid data character",
+			"x1b4n0q5v",
+		},
+		{
+			"too short checksum",
+			"li1dgmt3",
+		},
+		{
+			"checksum calculated with uppercase human-readable part",
+			"A1G7SGD8",
+		},
+		{
+			"mixed case",
+			"a12UEL5L",
+		},
+		{
+			"bad checksum",
+			"a12uel5m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := Bech32Decode(tt.s); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
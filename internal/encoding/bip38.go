@@ -0,0 +1,330 @@
+package encoding
+
+import (
+	"bytes"
+	"crypto/aes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+	"github.com/evercoinx/bitcoin/internal/hash"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	bip38EncryptedSize        = 37 // in bytes; 1B is flags, 4B is an address hash, 32B is a payload
+	bip38IntermediateCodeSize = 41 // in bytes; 8B is an owner entropy, 33B is a pass point
+
+	bip38FlagNonECMultiply = 0xc0
+	bip38FlagCompressed    = 0x20
+	bip38FlagLotSequence   = 0x04
+
+	// scrypt parameters to derive the encryption key from the passphrase
+	bip38ScryptN = 16384
+	bip38ScryptR = 8
+	bip38ScryptP = 8
+
+	// scrypt parameters to derive the encryption key from the pass point
+	bip38PointScryptN = 1024
+	bip38PointScryptR = 1
+	bip38PointScryptP = 1
+
+	bip38MaxLot      = 1<<20 - 1
+	bip38MaxSequence = 1<<12 - 1
+)
+
+var (
+	bip38PrefixNonECMultiply = []byte{0x01, 0x42}
+	bip38PrefixECMultiply    = []byte{0x01, 0x43}
+
+	bip38MagicIntermediate            = []byte{0x2c, 0xe9, 0xb3, 0xe1, 0xff, 0x39, 0xe2, 0x53}
+	bip38MagicIntermediateLotSequence = []byte{0x2c, 0xe9, 0xb3, 0xe1, 0xff, 0x39, 0xe2, 0x51}
+)
+
+var ErrBIP38Passphrase = errors.New("bip38: wrong passphrase")
+
+// BIP38LotSequence describes the optional lot and sequence numbers which an
+// owner of an intermediate code embeds into every key encrypted with it.
+type BIP38LotSequence struct {
+	Lot      uint32
+	Sequence uint32
+}
+
+// BIP38Encrypt encrypts a private key with a passphrase according to BIP 38
+// without EC multiplication. The compressed flag selects the form of the
+// public key whose address is committed to.
+//
+// The passphrase is used as given, so it should be normalized to the Unicode
+// NFC form by the caller like BIP 38 requires.
+func BIP38Encrypt(key *crypto.PrivateKey, passphrase string, compressed bool) (string, error) {
+	addrHash := bip38AddressHash(key.Public(), compressed)
+	derived, err := scrypt.Key([]byte(passphrase), addrHash, bip38ScryptN, bip38ScryptR, bip38ScryptP, 64)
+	if err != nil {
+		return "", fmt.Errorf("bip38: %w", err)
+	}
+
+	encrypted, err := bip38AESEncrypt(xorBytes(key.Bytes(), derived[:32]), derived[32:])
+	if err != nil {
+		return "", err
+	}
+
+	flags := byte(bip38FlagNonECMultiply)
+	if compressed {
+		flags |= bip38FlagCompressed
+	}
+	return Base58CheckEncodeVersion(bip38PrefixNonECMultiply, bytes.Join([][]byte{{flags}, addrHash, encrypted}, nil)), nil
+}
+
+// BIP38Decrypt decrypts a private key encrypted according to BIP 38 with or
+// without EC multiplication. It also reports whether the key uses the
+// compressed public key.
+func BIP38Decrypt(encrypted, passphrase string) (*crypto.PrivateKey, bool, error) {
+	prefix, data, err := Base58CheckDecodeVersion(encrypted, len(bip38PrefixNonECMultiply))
+	if err != nil {
+		return nil, false, fmt.Errorf("bip38: %w", err)
+	}
+	if len(data) != bip38EncryptedSize {
+		return nil, false, fmt.Errorf("bip38: encrypted key must be %d bytes, got %d", bip38EncryptedSize, len(data))
+	}
+
+	flags, addrHash, payload := data[0], data[1:5], data[5:]
+	compressed := flags&bip38FlagCompressed != 0
+
+	var key *crypto.PrivateKey
+	switch {
+	case bytes.Equal(prefix, bip38PrefixNonECMultiply) && flags&bip38FlagNonECMultiply == bip38FlagNonECMultiply:
+		key, err = bip38DecryptNonECMultiply(payload, addrHash, passphrase)
+	case bytes.Equal(prefix, bip38PrefixECMultiply) && flags&bip38FlagNonECMultiply == 0:
+		key, err = bip38DecryptECMultiply(payload, addrHash, passphrase, flags&bip38FlagLotSequence != 0)
+	default:
+		return nil, false, fmt.Errorf("bip38: unknown prefix 0x%x with flags 0x%02x", prefix, flags)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !bytes.Equal(bip38AddressHash(key.Public(), compressed), addrHash) {
+		return nil, false, ErrBIP38Passphrase
+	}
+	return key, compressed, nil
+}
+
+func bip38DecryptNonECMultiply(payload, addrHash []byte, passphrase string) (*crypto.PrivateKey, error) {
+	derived, err := scrypt.Key([]byte(passphrase), addrHash, bip38ScryptN, bip38ScryptR, bip38ScryptP, 64)
+	if err != nil {
+		return nil, fmt.Errorf("bip38: %w", err)
+	}
+
+	decrypted, err := bip38AESDecrypt(payload, derived[32:])
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := crypto.NewPrivateKey(xorBytes(decrypted, derived[:32]))
+	if err != nil {
+		return nil, ErrBIP38Passphrase
+	}
+	return key, nil
+}
+
+func bip38DecryptECMultiply(payload, addrHash []byte, passphrase string, lotSequence bool) (*crypto.PrivateKey, error) {
+	ownerEntropy, encrypted1, encrypted2 := payload[:8], payload[8:16], payload[16:]
+
+	passFactor, err := bip38PassFactor(passphrase, ownerEntropy, lotSequence)
+	if err != nil {
+		return nil, err
+	}
+	passPoint := passFactor.SerializeCompressed()
+
+	derived, err := scrypt.Key(passPoint, append(append([]byte(nil), addrHash...), ownerEntropy...),
+		bip38PointScryptN, bip38PointScryptR, bip38PointScryptP, 64)
+	if err != nil {
+		return nil, fmt.Errorf("bip38: %w", err)
+	}
+	block, err := aes.NewCipher(derived[32:])
+	if err != nil {
+		return nil, fmt.Errorf("bip38: %w", err)
+	}
+
+	// encryptedpart2 = AES(encryptedpart1[8:16] || seedb[16:24] xor derivedhalf1[16:32])
+	part2 := make([]byte, 16)
+	block.Decrypt(part2, encrypted2)
+	part2 = xorBytes(part2, derived[16:32])
+
+	// encryptedpart1 = AES(seedb[0:16] xor derivedhalf1[0:16])
+	part1 := make([]byte, 16)
+	block.Decrypt(part1, append(append([]byte(nil), encrypted1...), part2[:8]...))
+	part1 = xorBytes(part1, derived[:16])
+
+	seedB := append(part1, part2[8:]...)
+	key, err := passFactor.TweakMul(hash.Hash256(seedB))
+	if err != nil {
+		return nil, ErrBIP38Passphrase
+	}
+	return key, nil
+}
+
+// BIP38IntermediateCode returns the intermediate code of the passphrase,
+// which lets a third party generate keys encrypted with the passphrase
+// without learning them. The owner salt is read from rand. The lot and
+// sequence numbers are optional.
+func BIP38IntermediateCode(passphrase string, lotSequence *BIP38LotSequence, rand io.Reader) (string, error) {
+	saltSize := 8
+	magic := bip38MagicIntermediate
+	if lotSequence != nil {
+		if lotSequence.Lot > bip38MaxLot || lotSequence.Sequence > bip38MaxSequence {
+			return "", fmt.Errorf("bip38: lot %d or sequence %d is out of range", lotSequence.Lot, lotSequence.Sequence)
+		}
+		saltSize = 4
+		magic = bip38MagicIntermediateLotSequence
+	}
+
+	ownerEntropy := make([]byte, 8)
+	if _, err := io.ReadFull(rand, ownerEntropy[:saltSize]); err != nil {
+		return "", fmt.Errorf("bip38: unable to read random bytes: %w", err)
+	}
+	if lotSequence != nil {
+		n := lotSequence.Lot<<12 | lotSequence.Sequence
+		ownerEntropy[4], ownerEntropy[5], ownerEntropy[6], ownerEntropy[7] = byte(n>>24), byte(n>>16), byte(n>>8), byte(n)
+	}
+
+	passFactor, err := bip38PassFactor(passphrase, ownerEntropy, lotSequence != nil)
+	if err != nil {
+		return "", err
+	}
+	return Base58CheckEncodeVersion(magic, bytes.Join([][]byte{ownerEntropy, passFactor.SerializeCompressed()}, nil)), nil
+}
+
+// BIP38EncryptIntermediate generates a new private key encrypted with the
+// passphrase behind the intermediate code according to BIP 38 with EC
+// multiplication. The seed of the key is read from rand. It returns the
+// encrypted key and the P2PKH address of the key, whose private key only the
+// owner of the passphrase can decrypt.
+func BIP38EncryptIntermediate(code string, compressed bool, rand io.Reader) (encrypted, address string, err error) {
+	magic, data, err := Base58CheckDecodeVersion(code, len(bip38MagicIntermediate))
+	if err != nil {
+		return "", "", fmt.Errorf("bip38: %w", err)
+	}
+	if len(data) != bip38IntermediateCodeSize {
+		return "", "", fmt.Errorf("bip38: intermediate code must be %d bytes, got %d", bip38IntermediateCodeSize, len(data))
+	}
+
+	ownerEntropy, passPoint := data[:8], data[8:]
+	var flags byte
+	switch {
+	case bytes.Equal(magic, bip38MagicIntermediate):
+	case bytes.Equal(magic, bip38MagicIntermediateLotSequence):
+		flags |= bip38FlagLotSequence
+	default:
+		return "", "", errors.New("bip38: invalid intermediate code")
+	}
+	if compressed {
+		flags |= bip38FlagCompressed
+	}
+
+	pass, err := crypto.ParsePublicKey(passPoint)
+	if err != nil {
+		return "", "", fmt.Errorf("bip38: invalid pass point: %w", err)
+	}
+
+	seedB := make([]byte, 24)
+	if _, err := io.ReadFull(rand, seedB); err != nil {
+		return "", "", fmt.Errorf("bip38: unable to read random bytes: %w", err)
+	}
+	pub, err := pass.TweakMul(hash.Hash256(seedB))
+	if err != nil {
+		return "", "", fmt.Errorf("bip38: %w", err)
+	}
+
+	addrHash := bip38AddressHash(pub, compressed)
+	derived, err := scrypt.Key(passPoint, append(append([]byte(nil), addrHash...), ownerEntropy...),
+		bip38PointScryptN, bip38PointScryptR, bip38PointScryptP, 64)
+	if err != nil {
+		return "", "", fmt.Errorf("bip38: %w", err)
+	}
+	block, err := aes.NewCipher(derived[32:])
+	if err != nil {
+		return "", "", fmt.Errorf("bip38: %w", err)
+	}
+
+	part1 := make([]byte, 16)
+	block.Encrypt(part1, xorBytes(seedB[:16], derived[:16]))
+	part2 := make([]byte, 16)
+	block.Encrypt(part2, xorBytes(append(append([]byte(nil), part1[8:]...), seedB[16:]...), derived[16:32]))
+
+	payload := bytes.Join([][]byte{{flags}, addrHash, ownerEntropy, part1[:8], part2}, nil)
+	return Base58CheckEncodeVersion(bip38PrefixECMultiply, payload), bip38Address(pub, compressed), nil
+}
+
+// bip38PassFactor derives the pass factor from the passphrase and the owner
+// entropy and returns it as a private key.
+func bip38PassFactor(passphrase string, ownerEntropy []byte, lotSequence bool) (*crypto.PrivateKey, error) {
+	ownerSalt := ownerEntropy
+	if lotSequence {
+		ownerSalt = ownerEntropy[:4]
+	}
+
+	passFactor, err := scrypt.Key([]byte(passphrase), ownerSalt, bip38ScryptN, bip38ScryptR, bip38ScryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("bip38: %w", err)
+	}
+	if lotSequence {
+		passFactor = hash.Hash256(append(passFactor, ownerEntropy...))
+	}
+
+	key, err := crypto.NewPrivateKey(passFactor)
+	if err != nil {
+		return nil, fmt.Errorf("bip38: invalid pass factor: %w", err)
+	}
+	return key, nil
+}
+
+// bip38Address returns the P2PKH address of the public key.
+func bip38Address(pub *crypto.PublicKey, compressed bool) string {
+	pubKey := pub.SerializeUncompressed()
+	if compressed {
+		pubKey = pub.SerializeCompressed()
+	}
+	return Base58CheckEncode(hash.Hash160(pubKey), AddressVersionPublicKeyHash)
+}
+
+// bip38AddressHash returns the first 4 bytes of the double SHA-256 of the
+// P2PKH address of the public key, which is used as a salt and a check.
+func bip38AddressHash(pub *crypto.PublicKey, compressed bool) []byte {
+	return hash.Hash256([]byte(bip38Address(pub, compressed)))[:4]
+}
+
+// bip38AESEncrypt encrypts 32 bytes as two AES-256 blocks.
+func bip38AESEncrypt(data, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("bip38: %w", err)
+	}
+
+	out := make([]byte, 32)
+	block.Encrypt(out[:16], data[:16])
+	block.Encrypt(out[16:], data[16:])
+	return out, nil
+}
+
+// bip38AESDecrypt decrypts 32 bytes as two AES-256 blocks.
+func bip38AESDecrypt(data, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("bip38: %w", err)
+	}
+
+	out := make([]byte, 32)
+	block.Decrypt(out[:16], data[:16])
+	block.Decrypt(out[16:], data[16:])
+	return out, nil
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
@@ -0,0 +1,230 @@
+package encoding
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/evercoinx/bitcoin/internal/crypto"
+)
+
+func TestBIP38Encrypt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		key        string
+		passphrase string
+		compressed bool
+		want       string
+	}{
+		{
+			"uncompressed",
+			"cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+			"TestingOneTwoThree",
+			false,
+			"6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGg",
+		},
+		{
+			"compressed",
+			"cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+			"TestingOneTwoThree",
+			true,
+			"6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo",
+		},
+		{
+			"compressed another key",
+			"09c2686880095b1a4c249ee3ac4eea8a014f11e6f986d0b5025ac1f39afbd9ae",
+			"Satoshi",
+			true,
+			"6PYLtMnXvfG3oJde97zRyLYFZCYizPU5T3LwgdYJz1fRhh16bU7u6PPmY7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyBytes, err := hex.DecodeString(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, err := crypto.NewPrivateKey(keyBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := BIP38Encrypt(key, tt.passphrase, tt.compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("%s != %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBIP38Decrypt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		encrypted  string
+		passphrase string
+		want       string
+		compressed bool
+	}{
+		{
+			"non-ec-multiply uncompressed",
+			"6PRNFFkZc2NZ6dJqFfhRoFNMR9Lnyj7dYGrzdgXXVMXcxoKTePPX1dWByq",
+			"Satoshi",
+			"09c2686880095b1a4c249ee3ac4eea8a014f11e6f986d0b5025ac1f39afbd9ae",
+			false,
+		},
+		{
+			"non-ec-multiply compressed",
+			"6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo",
+			"TestingOneTwoThree",
+			"cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+			true,
+		},
+		{
+			"ec-multiply",
+			"6PfQu77ygVyJLZjfvMLyhLMQbYnu5uguoJJ4kMCLqWwPEdfpwANVS76gTX",
+			"TestingOneTwoThree",
+			"a43a940577f4e97f5c4d39eb14ff083a98187c64ea7c99ef7ce460833959a519",
+			false,
+		},
+		{
+			"ec-multiply with lot and sequence",
+			"6PgNBNNzDkKdhkT6uJntUXwwzQV8Rr2tZcbkDcuC9DZRsS6AtHts4Ypo1j",
+			"MOLON LABE",
+			"44ea95afbf138356a05ea32110dfd627232d0f2991ad221187be356f19fa8190",
+			false,
+		},
+		{
+			"ec-multiply with lot and sequence and unicode passphrase",
+			"6PgGWtx25kUg8QWvwuJAgorN6k9FbE25rv5dMRwu5SKMnfpfVe5mar2ngH",
+			"\u039c\u039f\u039b\u03a9\u039d \u039b\u0391\u0392\u0395",
+			"ca2759aa4adb0f96c414f36abeb8db59342985be9fa50faac228c8e7d90e3006",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, compressed, err := BIP38Decrypt(tt.encrypted, tt.passphrase)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(key.Bytes()); got != tt.want {
+				t.Fatalf("%s != %s", got, tt.want)
+			}
+			if compressed != tt.compressed {
+				t.Fatalf("%t != %t", compressed, tt.compressed)
+			}
+		})
+	}
+}
+
+func TestBIP38DecryptWrongPassphrase(t *testing.T) {
+	t.Parallel()
+
+	for _, encrypted := range []string{
+		"6PYLtMnXvfG3oJde97zRyLYFZCYizPU5T3LwgdYJz1fRhh16bU7u6PPmY7",
+		"6PfLGnQs6VZnrNpmVKfjotbnQuaJK4KZoPFrAjx1JMJUa1Ft8gnf5WxfKd",
+	} {
+		if _, _, err := BIP38Decrypt(encrypted, "satoshi"); !errors.Is(err, ErrBIP38Passphrase) {
+			t.Fatalf("%s: unexpected error: %v", encrypted, err)
+		}
+	}
+
+	if _, _, err := BIP38Decrypt("5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", "Satoshi"); err == nil {
+		t.Fatal("expected error for a non-bip38 string")
+	}
+}
+
+func TestBIP38IntermediateCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		passphrase  string
+		lotSequence *BIP38LotSequence
+		salt        string
+		want        string
+	}{
+		{
+			"without lot and sequence",
+			"TestingOneTwoThree",
+			nil,
+			"a50dba6772cb9383",
+			"passphrasepxFy57B9v8HtUsszJYKReoNDV6VHjUSGt8EVJmux9n1J3Ltf1gRxyDGXqnf9qm",
+		},
+		{
+			"with lot and sequence",
+			"MOLON LABE",
+			&BIP38LotSequence{Lot: 263183, Sequence: 1},
+			"4fca5a97",
+			"passphraseaB8feaLQDENqCgr4gKZpmf4VoaT6qdjJNJiv7fsKvjqavcJxvuR1hy25aTu5sX",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salt, err := hex.DecodeString(tt.salt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := BIP38IntermediateCode(tt.passphrase, tt.lotSequence, bytes.NewReader(salt))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("%s != %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := BIP38IntermediateCode("Satoshi", &BIP38LotSequence{Lot: 1 << 20}, rand.Reader); err == nil {
+		t.Fatal("expected error for an out of range lot")
+	}
+}
+
+func TestBIP38EncryptIntermediate(t *testing.T) {
+	t.Parallel()
+
+	const passphrase = "Satoshi"
+	code, err := BIP38IntermediateCode(passphrase, &BIP38LotSequence{Lot: 1, Sequence: 2}, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, compressed := range []bool{false, true} {
+		encrypted, addr, err := BIP38EncryptIntermediate(code, compressed, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(encrypted, "6P") {
+			t.Fatalf("unexpected encrypted key: %s", encrypted)
+		}
+
+		key, gotCompressed, err := BIP38Decrypt(encrypted, passphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotCompressed != compressed {
+			t.Fatalf("%t != %t", gotCompressed, compressed)
+		}
+		if got := bip38Address(key.Public(), compressed); got != addr {
+			t.Fatalf("%s != %s", got, addr)
+		}
+	}
+
+	if _, _, err := BIP38EncryptIntermediate("6PfLGnQs6VZnrNpmVKfjotbnQuaJK4KZoPFrAjx1JMJUa1Ft8gnf5WxfKd", false, rand.Reader); err == nil {
+		t.Fatal("expected error for an invalid intermediate code")
+	}
+}
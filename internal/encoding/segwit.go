@@ -0,0 +1,92 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	witnessVersionMax = 16
+
+	witnessProgramMinSize = 2  // in bytes
+	witnessProgramMaxSize = 40 // in bytes
+
+	witnessV0PubKeyHashSize = 20 // in bytes
+	witnessV0ScriptHashSize = 32 // in bytes
+)
+
+// SegwitAddressEncode encodes a witness program of the given version into a
+// segwit address with the human-readable part of a network. Version 0 uses
+// Bech32 according to BIP 173, later versions use Bech32m according to
+// BIP 350.
+func SegwitAddressEncode(hrp string, version byte, program []byte) (string, error) {
+	if err := validateWitnessProgram(version, program); err != nil {
+		return "", err
+	}
+
+	data, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("segwit: %w", err)
+	}
+
+	addr, err := Bech32Encode(hrp, append([]byte{version}, data...), witnessEncoding(version))
+	if err != nil {
+		return "", fmt.Errorf("segwit: %w", err)
+	}
+	return addr, nil
+}
+
+// SegwitAddressDecode decodes a segwit address into a witness version and a
+// witness program. The address must have the given human-readable part and
+// the checksum variant required by its version.
+func SegwitAddressDecode(hrp, addr string) (version byte, program []byte, err error) {
+	gotHRP, data, enc, err := Bech32Decode(addr)
+	if err != nil {
+		return 0, nil, fmt.Errorf("segwit: %w", err)
+	}
+	if gotHRP != hrp {
+		return 0, nil, fmt.Errorf("segwit: human-readable part must be %s, got %s", hrp, gotHRP)
+	}
+	if len(data) == 0 {
+		return 0, nil, errors.New("segwit: witness version is missing")
+	}
+
+	version = data[0]
+	if version > witnessVersionMax {
+		return 0, nil, fmt.Errorf("segwit: invalid witness version %d", version)
+	}
+	if want := witnessEncoding(version); enc != want {
+		return 0, nil, fmt.Errorf("segwit: witness version %d requires %s, got %s", version, want, enc)
+	}
+
+	program, err = convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, fmt.Errorf("segwit: %w", err)
+	}
+	if err := validateWitnessProgram(version, program); err != nil {
+		return 0, nil, err
+	}
+	return version, program, nil
+}
+
+func validateWitnessProgram(version byte, program []byte) error {
+	if version > witnessVersionMax {
+		return fmt.Errorf("segwit: invalid witness version %d", version)
+	}
+	if len(program) < witnessProgramMinSize || len(program) > witnessProgramMaxSize {
+		return fmt.Errorf("segwit: witness program must be %d to %d bytes, got %d",
+			witnessProgramMinSize, witnessProgramMaxSize, len(program))
+	}
+	if version == 0 && len(program) != witnessV0PubKeyHashSize && len(program) != witnessV0ScriptHashSize {
+		return fmt.Errorf("segwit: witness v0 program must be %d or %d bytes, got %d",
+			witnessV0PubKeyHashSize, witnessV0ScriptHashSize, len(program))
+	}
+	return nil
+}
+
+func witnessEncoding(version byte) Bech32Encoding {
+	if version == 0 {
+		return EncodingBech32
+	}
+	return EncodingBech32m
+}
@@ -0,0 +1,234 @@
+package encoding
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSegwitAddressDecode(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors are taken from BIP 350.
+	tests := []struct {
+		name    string
+		hrp     string
+		address string
+		version byte
+		program string
+	}{
+		{
+			"p2wpkh uppercase",
+			"bc",
+			"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",
+			0,
+			"751e76e8199196d454941c45d1b3a323f1433bd6",
+		},
+		{
+			"p2wsh testnet",
+			"tb",
+			"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7",
+			0,
+			"1863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262",
+		},
+		{
+			"version 1 40-byte program",
+			"bc",
+			"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y",
+			1,
+			"751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6",
+		},
+		{
+			"version 16 2-byte program",
+			"bc",
+			"BC1SW50QGDZ25J",
+			16,
+			"751e",
+		},
+		{
+			"version 2 16-byte program",
+			"bc",
+			"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs",
+			2,
+			"751e76e8199196d454941c45d1b3a323",
+		},
+		{
+			"p2wsh with leading zeros",
+			"tb",
+			"tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy",
+			0,
+			"000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433",
+		},
+		{
+			"p2tr testnet",
+			"tb",
+			"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c",
+			1,
+			"000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433",
+		},
+		{
+			"p2tr mainnet",
+			"bc",
+			"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+			1,
+			"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, program, err := SegwitAddressDecode(tt.hrp, tt.address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != tt.version {
+				t.Fatalf("%d != %d", version, tt.version)
+			}
+			if got := hex.EncodeToString(program); got != tt.program {
+				t.Fatalf("%s != %s", got, tt.program)
+			}
+
+			addr, err := SegwitAddressEncode(tt.hrp, version, program)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := strings.ToLower(tt.address); addr != want {
+				t.Fatalf("%s != %s", addr, want)
+			}
+		})
+	}
+}
+
+func TestSegwitAddressDecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors are taken from BIP 350.
+	tests := []struct {
+		name    string
+		hrp     string
+		address string
+	}{
+		{
+			"invalid human-readable part",
+			"bc",
+			"tc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut",
+		},
+		{
+			"version 1 with bech32 checksum",
+			"bc",
+			"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd",
+		},
+		{
+			"version 2 with bech32 checksum",
+			"tb",
+			"tb1z0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqglt7rf",
+		},
+		{
+			"version 16 with bech32 checksum",
+			"bc",
+			"BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL",
+		},
+		{
+			"version 0 with bech32m checksum",
+			"bc",
+			"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh",
+		},
+		{
+			"version 0 p2wsh with bech32m checksum",
+			"tb",
+			"tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47",
+		},
+		{
+			"invalid character in checksum",
+			"bc",
+			"bc1p38j9r5y49hruaue7wxjce0updqjuyyx0kh56v8s25huc6995vvpql3jow4",
+		},
+		{
+			"invalid witness version",
+			"bc",
+			"BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R",
+		},
+		{
+			"invalid program length",
+			"bc",
+			"bc1pw5dgrnzv",
+		},
+		{
+			"too long program",
+			"bc",
+			"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v8n0nx0muaewav253zgeav",
+		},
+		{
+			"invalid program length for version 0",
+			"bc",
+			"BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P",
+		},
+		{
+			"mixed case",
+			"tb",
+			"tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq47Zagq",
+		},
+		{
+			"more than 4 padding bits",
+			"bc",
+			"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v07qwwzcrf",
+		},
+		{
+			"non-zero padding",
+			"tb",
+			"tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vpggkg4j",
+		},
+		{
+			"empty data",
+			"bc",
+			"bc1gmk9yu",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := SegwitAddressDecode(tt.hrp, tt.address); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestSegwitAddressEncodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version byte
+		program []byte
+	}{
+		{
+			"invalid witness version",
+			17,
+			make([]byte, 32),
+		},
+		{
+			"too short program",
+			1,
+			make([]byte, 1),
+		},
+		{
+			"too long program",
+			1,
+			make([]byte, 41),
+		},
+		{
+			"invalid program length for version 0",
+			0,
+			make([]byte, 16),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SegwitAddressEncode("bc", tt.version, tt.program); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+type WIFVersion byte
+
+const (
+	WIFVersionMainnet WIFVersion = 0x80
+	WIFVersionTestnet WIFVersion = 0xef
+)
+
+const (
+	wifKeySize        = 32
+	wifCompressedFlag = 0x01
+)
+
+// WIF describes a private key in the Wallet Import Format.
+type WIF struct {
+	Key     []byte
+	Version WIFVersion
+	// Compressed tells whether the public key of the private key is used
+	// in the compressed form.
+	Compressed bool
+}
+
+// WIFEncode encodes a 32-byte private key in the Wallet Import Format.
+func WIFEncode(key []byte, version WIFVersion, compressed bool) (string, error) {
+	if len(key) != wifKeySize {
+		return "", fmt.Errorf("wif: private key must be %d bytes, got %d", wifKeySize, len(key))
+	}
+	if version != WIFVersionMainnet && version != WIFVersionTestnet {
+		return "", fmt.Errorf("wif: unknown version 0x%02x", byte(version))
+	}
+
+	payload := append([]byte(nil), key...)
+	if compressed {
+		payload = append(payload, wifCompressedFlag)
+	}
+	return Base58CheckEncodeVersion([]byte{byte(version)}, payload), nil
+}
+
+// WIFDecode decodes a private key in the Wallet Import Format.
+func WIFDecode(wif string) (*WIF, error) {
+	ver, payload, err := Base58CheckDecodeVersion(wif, 1)
+	if err != nil {
+		return nil, fmt.Errorf("wif: %w", err)
+	}
+
+	version := WIFVersion(ver[0])
+	if version != WIFVersionMainnet && version != WIFVersionTestnet {
+		return nil, fmt.Errorf("wif: unknown version 0x%02x", byte(version))
+	}
+
+	compressed := len(payload) == wifKeySize+1
+	if compressed {
+		if payload[wifKeySize] != wifCompressedFlag {
+			return nil, errors.New("wif: invalid compression flag")
+		}
+		payload = payload[:wifKeySize]
+	}
+	if len(payload) != wifKeySize {
+		return nil, fmt.Errorf("wif: private key must be %d bytes, got %d", wifKeySize, len(payload))
+	}
+
+	return &WIF{
+		Key:        payload,
+		Version:    version,
+		Compressed: compressed,
+	}, nil
+}
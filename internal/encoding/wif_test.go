@@ -0,0 +1,102 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+const wifTestKey = "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d"
+
+func TestWIFEncode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		version    WIFVersion
+		compressed bool
+		want       string
+	}{
+		{
+			"mainnet uncompressed",
+			WIFVersionMainnet,
+			false,
+			"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ",
+		},
+		{
+			"mainnet compressed",
+			WIFVersionMainnet,
+			true,
+			"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617",
+		},
+		{
+			"testnet uncompressed",
+			WIFVersionTestnet,
+			false,
+			"91gGn1HgSap6CbU12F6z3pJri26xzp7Ay1VW6NHCoEayNXwRpu2",
+		},
+		{
+			"testnet compressed",
+			WIFVersionTestnet,
+			true,
+			"cMzLdeGd5vEqxB8B6VFQoRopQ3sLAAvEzDAoQgvX54xwofSWj1fx",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := hex.DecodeString(wifTestKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := WIFEncode(key, tt.version, tt.compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("%s != %s", got, tt.want)
+			}
+
+			decoded, err := WIFDecode(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(decoded.Key, key) || decoded.Version != tt.version || decoded.Compressed != tt.compressed {
+				t.Fatalf("%x, 0x%02x, %t != %x, 0x%02x, %t",
+					decoded.Key, byte(decoded.Version), decoded.Compressed, key, byte(tt.version), tt.compressed)
+			}
+		})
+	}
+}
+
+func TestWIFDecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		wif  string
+	}{
+		{
+			"bad checksum",
+			"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTK",
+		},
+		{
+			"address version",
+			"19g6oo8foQF5jfqK9gH2bLkFNwgCenRBPD",
+		},
+		{
+			"invalid compression flag",
+			// mainnet key followed by 0x02 instead of 0x01
+			"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvWxyf5d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := WIFDecode(tt.wif); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -20,3 +20,17 @@ func Hash256(data []byte) []byte {
 	d2 := sha256.Sum256(d[:])
 	return d2[:]
 }
+
+// TaggedHash hashes input data with SHA-256 prefixed twice by the SHA-256 of
+// the tag as specified by BIP 340, so that hashes used in different contexts
+// never collide.
+func TaggedHash(tag string, data ...[]byte) []byte {
+	t := sha256.Sum256([]byte(tag))
+	d := sha256.New()
+	d.Write(t[:])
+	d.Write(t[:])
+	for _, b := range data {
+		d.Write(b)
+	}
+	return d.Sum(nil)
+}
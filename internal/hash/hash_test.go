@@ -86,3 +86,47 @@ func TestHash256(t *testing.T) {
 		})
 	}
 }
+
+func TestTaggedHash(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		tag  string
+		data [][]byte
+		want string
+	}{
+		{
+			"empty data",
+			"BIP0340/challenge",
+			nil,
+			"c216d352f5818b7b4beacd4ae0a26fe888080823d2a598856661bcd54f1b3713",
+		},
+		{
+			"alphanumeric data",
+			"TapLeaf",
+			[][]byte{[]byte("Test123")},
+			"c8bf926a5c633b4bda3f3cd8a827c53ccbb45508fa542884eef4470779cc6ca5",
+		},
+		{
+			"punctuation data in chunks",
+			"BIP0340/aux",
+			[][]byte{[]byte("Hello, "), []byte("world!")},
+			"f1ccc5e047589fc08931f98d5f03f4f5ddf10ad61385d2f20eb3034023ce6383",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := hex.DecodeString(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := TaggedHash(tt.tag, tt.data...)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("%x != %x", got, want)
+			}
+		})
+	}
+}